@@ -0,0 +1,283 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: api/boards.proto
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	BoardService_CreateBoard_FullMethodName = "/api.BoardService/CreateBoard"
+	BoardService_GetBoard_FullMethodName    = "/api.BoardService/GetBoard"
+	BoardService_UpdateBoard_FullMethodName = "/api.BoardService/UpdateBoard"
+	BoardService_DeleteBoard_FullMethodName = "/api.BoardService/DeleteBoard"
+	BoardService_ListBoards_FullMethodName  = "/api.BoardService/ListBoards"
+)
+
+// BoardServiceClient is the client API for BoardService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BoardServiceClient interface {
+	CreateBoard(ctx context.Context, in *CreateBoardRequest, opts ...grpc.CallOption) (*CreateBoardResponse, error)
+	GetBoard(ctx context.Context, in *GetBoardRequest, opts ...grpc.CallOption) (*GetBoardResponse, error)
+	UpdateBoard(ctx context.Context, in *UpdateBoardRequest, opts ...grpc.CallOption) (*UpdateBoardResponse, error)
+	DeleteBoard(ctx context.Context, in *DeleteBoardRequest, opts ...grpc.CallOption) (*DeleteBoardResponse, error)
+	ListBoards(ctx context.Context, in *ListBoardsRequest, opts ...grpc.CallOption) (BoardService_ListBoardsClient, error)
+}
+
+type boardServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBoardServiceClient(cc grpc.ClientConnInterface) BoardServiceClient {
+	return &boardServiceClient{cc}
+}
+
+func (c *boardServiceClient) CreateBoard(ctx context.Context, in *CreateBoardRequest, opts ...grpc.CallOption) (*CreateBoardResponse, error) {
+	out := new(CreateBoardResponse)
+	err := c.cc.Invoke(ctx, BoardService_CreateBoard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) GetBoard(ctx context.Context, in *GetBoardRequest, opts ...grpc.CallOption) (*GetBoardResponse, error) {
+	out := new(GetBoardResponse)
+	err := c.cc.Invoke(ctx, BoardService_GetBoard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) UpdateBoard(ctx context.Context, in *UpdateBoardRequest, opts ...grpc.CallOption) (*UpdateBoardResponse, error) {
+	out := new(UpdateBoardResponse)
+	err := c.cc.Invoke(ctx, BoardService_UpdateBoard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) DeleteBoard(ctx context.Context, in *DeleteBoardRequest, opts ...grpc.CallOption) (*DeleteBoardResponse, error) {
+	out := new(DeleteBoardResponse)
+	err := c.cc.Invoke(ctx, BoardService_DeleteBoard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *boardServiceClient) ListBoards(ctx context.Context, in *ListBoardsRequest, opts ...grpc.CallOption) (BoardService_ListBoardsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BoardService_ServiceDesc.Streams[0], BoardService_ListBoards_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &boardServiceListBoardsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BoardService_ListBoardsClient interface {
+	Recv() (*ListBoardsResponse, error)
+	grpc.ClientStream
+}
+
+type boardServiceListBoardsClient struct {
+	grpc.ClientStream
+}
+
+func (x *boardServiceListBoardsClient) Recv() (*ListBoardsResponse, error) {
+	m := new(ListBoardsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BoardServiceServer is the server API for BoardService service.
+// All implementations should embed UnimplementedBoardServiceServer
+// for forward compatibility
+type BoardServiceServer interface {
+	CreateBoard(context.Context, *CreateBoardRequest) (*CreateBoardResponse, error)
+	GetBoard(context.Context, *GetBoardRequest) (*GetBoardResponse, error)
+	UpdateBoard(context.Context, *UpdateBoardRequest) (*UpdateBoardResponse, error)
+	DeleteBoard(context.Context, *DeleteBoardRequest) (*DeleteBoardResponse, error)
+	ListBoards(*ListBoardsRequest, BoardService_ListBoardsServer) error
+}
+
+// UnimplementedBoardServiceServer should be embedded to have forward compatible implementations.
+type UnimplementedBoardServiceServer struct {
+}
+
+func (UnimplementedBoardServiceServer) CreateBoard(context.Context, *CreateBoardRequest) (*CreateBoardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBoard not implemented")
+}
+func (UnimplementedBoardServiceServer) GetBoard(context.Context, *GetBoardRequest) (*GetBoardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBoard not implemented")
+}
+func (UnimplementedBoardServiceServer) UpdateBoard(context.Context, *UpdateBoardRequest) (*UpdateBoardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBoard not implemented")
+}
+func (UnimplementedBoardServiceServer) DeleteBoard(context.Context, *DeleteBoardRequest) (*DeleteBoardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBoard not implemented")
+}
+func (UnimplementedBoardServiceServer) ListBoards(*ListBoardsRequest, BoardService_ListBoardsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListBoards not implemented")
+}
+
+// UnsafeBoardServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BoardServiceServer will
+// result in compilation errors.
+type UnsafeBoardServiceServer interface {
+	mustEmbedUnimplementedBoardServiceServer()
+}
+
+func RegisterBoardServiceServer(s grpc.ServiceRegistrar, srv BoardServiceServer) {
+	s.RegisterService(&BoardService_ServiceDesc, srv)
+}
+
+func _BoardService_CreateBoard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBoardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).CreateBoard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BoardService_CreateBoard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).CreateBoard(ctx, req.(*CreateBoardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_GetBoard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBoardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).GetBoard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BoardService_GetBoard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).GetBoard(ctx, req.(*GetBoardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_UpdateBoard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBoardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).UpdateBoard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BoardService_UpdateBoard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).UpdateBoard(ctx, req.(*UpdateBoardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_DeleteBoard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBoardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BoardServiceServer).DeleteBoard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BoardService_DeleteBoard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BoardServiceServer).DeleteBoard(ctx, req.(*DeleteBoardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BoardService_ListBoards_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListBoardsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BoardServiceServer).ListBoards(m, &boardServiceListBoardsServer{stream})
+}
+
+type BoardService_ListBoardsServer interface {
+	Send(*ListBoardsResponse) error
+	grpc.ServerStream
+}
+
+type boardServiceListBoardsServer struct {
+	grpc.ServerStream
+}
+
+func (x *boardServiceListBoardsServer) Send(m *ListBoardsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// BoardService_ServiceDesc is the grpc.ServiceDesc for BoardService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BoardService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.BoardService",
+	HandlerType: (*BoardServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateBoard",
+			Handler:    _BoardService_CreateBoard_Handler,
+		},
+		{
+			MethodName: "GetBoard",
+			Handler:    _BoardService_GetBoard_Handler,
+		},
+		{
+			MethodName: "UpdateBoard",
+			Handler:    _BoardService_UpdateBoard_Handler,
+		},
+		{
+			MethodName: "DeleteBoard",
+			Handler:    _BoardService_DeleteBoard_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListBoards",
+			Handler:       _BoardService_ListBoards_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/boards.proto",
+}
@@ -0,0 +1,398 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: api/sprints.proto
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	SprintService_CreateSprint_FullMethodName      = "/api.SprintService/CreateSprint"
+	SprintService_GetSprint_FullMethodName         = "/api.SprintService/GetSprint"
+	SprintService_UpdateSprint_FullMethodName      = "/api.SprintService/UpdateSprint"
+	SprintService_DeleteSprint_FullMethodName      = "/api.SprintService/DeleteSprint"
+	SprintService_ListSprints_FullMethodName       = "/api.SprintService/ListSprints"
+	SprintService_AddSprintTasks_FullMethodName    = "/api.SprintService/AddSprintTasks"
+	SprintService_RemoveSprintTasks_FullMethodName = "/api.SprintService/RemoveSprintTasks"
+	SprintService_GetBurndown_FullMethodName       = "/api.SprintService/GetBurndown"
+)
+
+// SprintServiceClient is the client API for SprintService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SprintServiceClient interface {
+	CreateSprint(ctx context.Context, in *CreateSprintRequest, opts ...grpc.CallOption) (*CreateSprintResponse, error)
+	GetSprint(ctx context.Context, in *GetSprintRequest, opts ...grpc.CallOption) (*GetSprintResponse, error)
+	UpdateSprint(ctx context.Context, in *UpdateSprintRequest, opts ...grpc.CallOption) (*UpdateSprintResponse, error)
+	DeleteSprint(ctx context.Context, in *DeleteSprintRequest, opts ...grpc.CallOption) (*DeleteSprintResponse, error)
+	ListSprints(ctx context.Context, in *ListSprintsRequest, opts ...grpc.CallOption) (SprintService_ListSprintsClient, error)
+	AddSprintTasks(ctx context.Context, in *AddSprintTasksRequest, opts ...grpc.CallOption) (*AddSprintTasksResponse, error)
+	RemoveSprintTasks(ctx context.Context, in *RemoveSprintTasksRequest, opts ...grpc.CallOption) (*RemoveSprintTasksResponse, error)
+	// Remaining story points per day, worked out from the revisions of the
+	// sprint tasks.
+	GetBurndown(ctx context.Context, in *GetBurndownRequest, opts ...grpc.CallOption) (*GetBurndownResponse, error)
+}
+
+type sprintServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSprintServiceClient(cc grpc.ClientConnInterface) SprintServiceClient {
+	return &sprintServiceClient{cc}
+}
+
+func (c *sprintServiceClient) CreateSprint(ctx context.Context, in *CreateSprintRequest, opts ...grpc.CallOption) (*CreateSprintResponse, error) {
+	out := new(CreateSprintResponse)
+	err := c.cc.Invoke(ctx, SprintService_CreateSprint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sprintServiceClient) GetSprint(ctx context.Context, in *GetSprintRequest, opts ...grpc.CallOption) (*GetSprintResponse, error) {
+	out := new(GetSprintResponse)
+	err := c.cc.Invoke(ctx, SprintService_GetSprint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sprintServiceClient) UpdateSprint(ctx context.Context, in *UpdateSprintRequest, opts ...grpc.CallOption) (*UpdateSprintResponse, error) {
+	out := new(UpdateSprintResponse)
+	err := c.cc.Invoke(ctx, SprintService_UpdateSprint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sprintServiceClient) DeleteSprint(ctx context.Context, in *DeleteSprintRequest, opts ...grpc.CallOption) (*DeleteSprintResponse, error) {
+	out := new(DeleteSprintResponse)
+	err := c.cc.Invoke(ctx, SprintService_DeleteSprint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sprintServiceClient) ListSprints(ctx context.Context, in *ListSprintsRequest, opts ...grpc.CallOption) (SprintService_ListSprintsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &SprintService_ServiceDesc.Streams[0], SprintService_ListSprints_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sprintServiceListSprintsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SprintService_ListSprintsClient interface {
+	Recv() (*ListSprintsResponse, error)
+	grpc.ClientStream
+}
+
+type sprintServiceListSprintsClient struct {
+	grpc.ClientStream
+}
+
+func (x *sprintServiceListSprintsClient) Recv() (*ListSprintsResponse, error) {
+	m := new(ListSprintsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *sprintServiceClient) AddSprintTasks(ctx context.Context, in *AddSprintTasksRequest, opts ...grpc.CallOption) (*AddSprintTasksResponse, error) {
+	out := new(AddSprintTasksResponse)
+	err := c.cc.Invoke(ctx, SprintService_AddSprintTasks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sprintServiceClient) RemoveSprintTasks(ctx context.Context, in *RemoveSprintTasksRequest, opts ...grpc.CallOption) (*RemoveSprintTasksResponse, error) {
+	out := new(RemoveSprintTasksResponse)
+	err := c.cc.Invoke(ctx, SprintService_RemoveSprintTasks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sprintServiceClient) GetBurndown(ctx context.Context, in *GetBurndownRequest, opts ...grpc.CallOption) (*GetBurndownResponse, error) {
+	out := new(GetBurndownResponse)
+	err := c.cc.Invoke(ctx, SprintService_GetBurndown_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SprintServiceServer is the server API for SprintService service.
+// All implementations should embed UnimplementedSprintServiceServer
+// for forward compatibility
+type SprintServiceServer interface {
+	CreateSprint(context.Context, *CreateSprintRequest) (*CreateSprintResponse, error)
+	GetSprint(context.Context, *GetSprintRequest) (*GetSprintResponse, error)
+	UpdateSprint(context.Context, *UpdateSprintRequest) (*UpdateSprintResponse, error)
+	DeleteSprint(context.Context, *DeleteSprintRequest) (*DeleteSprintResponse, error)
+	ListSprints(*ListSprintsRequest, SprintService_ListSprintsServer) error
+	AddSprintTasks(context.Context, *AddSprintTasksRequest) (*AddSprintTasksResponse, error)
+	RemoveSprintTasks(context.Context, *RemoveSprintTasksRequest) (*RemoveSprintTasksResponse, error)
+	// Remaining story points per day, worked out from the revisions of the
+	// sprint tasks.
+	GetBurndown(context.Context, *GetBurndownRequest) (*GetBurndownResponse, error)
+}
+
+// UnimplementedSprintServiceServer should be embedded to have forward compatible implementations.
+type UnimplementedSprintServiceServer struct {
+}
+
+func (UnimplementedSprintServiceServer) CreateSprint(context.Context, *CreateSprintRequest) (*CreateSprintResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSprint not implemented")
+}
+func (UnimplementedSprintServiceServer) GetSprint(context.Context, *GetSprintRequest) (*GetSprintResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSprint not implemented")
+}
+func (UnimplementedSprintServiceServer) UpdateSprint(context.Context, *UpdateSprintRequest) (*UpdateSprintResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSprint not implemented")
+}
+func (UnimplementedSprintServiceServer) DeleteSprint(context.Context, *DeleteSprintRequest) (*DeleteSprintResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSprint not implemented")
+}
+func (UnimplementedSprintServiceServer) ListSprints(*ListSprintsRequest, SprintService_ListSprintsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListSprints not implemented")
+}
+func (UnimplementedSprintServiceServer) AddSprintTasks(context.Context, *AddSprintTasksRequest) (*AddSprintTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSprintTasks not implemented")
+}
+func (UnimplementedSprintServiceServer) RemoveSprintTasks(context.Context, *RemoveSprintTasksRequest) (*RemoveSprintTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveSprintTasks not implemented")
+}
+func (UnimplementedSprintServiceServer) GetBurndown(context.Context, *GetBurndownRequest) (*GetBurndownResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBurndown not implemented")
+}
+
+// UnsafeSprintServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SprintServiceServer will
+// result in compilation errors.
+type UnsafeSprintServiceServer interface {
+	mustEmbedUnimplementedSprintServiceServer()
+}
+
+func RegisterSprintServiceServer(s grpc.ServiceRegistrar, srv SprintServiceServer) {
+	s.RegisterService(&SprintService_ServiceDesc, srv)
+}
+
+func _SprintService_CreateSprint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSprintRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SprintServiceServer).CreateSprint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SprintService_CreateSprint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SprintServiceServer).CreateSprint(ctx, req.(*CreateSprintRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SprintService_GetSprint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSprintRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SprintServiceServer).GetSprint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SprintService_GetSprint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SprintServiceServer).GetSprint(ctx, req.(*GetSprintRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SprintService_UpdateSprint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSprintRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SprintServiceServer).UpdateSprint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SprintService_UpdateSprint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SprintServiceServer).UpdateSprint(ctx, req.(*UpdateSprintRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SprintService_DeleteSprint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSprintRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SprintServiceServer).DeleteSprint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SprintService_DeleteSprint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SprintServiceServer).DeleteSprint(ctx, req.(*DeleteSprintRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SprintService_ListSprints_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListSprintsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SprintServiceServer).ListSprints(m, &sprintServiceListSprintsServer{stream})
+}
+
+type SprintService_ListSprintsServer interface {
+	Send(*ListSprintsResponse) error
+	grpc.ServerStream
+}
+
+type sprintServiceListSprintsServer struct {
+	grpc.ServerStream
+}
+
+func (x *sprintServiceListSprintsServer) Send(m *ListSprintsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _SprintService_AddSprintTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSprintTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SprintServiceServer).AddSprintTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SprintService_AddSprintTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SprintServiceServer).AddSprintTasks(ctx, req.(*AddSprintTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SprintService_RemoveSprintTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveSprintTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SprintServiceServer).RemoveSprintTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SprintService_RemoveSprintTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SprintServiceServer).RemoveSprintTasks(ctx, req.(*RemoveSprintTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SprintService_GetBurndown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBurndownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SprintServiceServer).GetBurndown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SprintService_GetBurndown_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SprintServiceServer).GetBurndown(ctx, req.(*GetBurndownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SprintService_ServiceDesc is the grpc.ServiceDesc for SprintService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SprintService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.SprintService",
+	HandlerType: (*SprintServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateSprint",
+			Handler:    _SprintService_CreateSprint_Handler,
+		},
+		{
+			MethodName: "GetSprint",
+			Handler:    _SprintService_GetSprint_Handler,
+		},
+		{
+			MethodName: "UpdateSprint",
+			Handler:    _SprintService_UpdateSprint_Handler,
+		},
+		{
+			MethodName: "DeleteSprint",
+			Handler:    _SprintService_DeleteSprint_Handler,
+		},
+		{
+			MethodName: "AddSprintTasks",
+			Handler:    _SprintService_AddSprintTasks_Handler,
+		},
+		{
+			MethodName: "RemoveSprintTasks",
+			Handler:    _SprintService_RemoveSprintTasks_Handler,
+		},
+		{
+			MethodName: "GetBurndown",
+			Handler:    _SprintService_GetBurndown_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListSprints",
+			Handler:       _SprintService_ListSprints_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/sprints.proto",
+}
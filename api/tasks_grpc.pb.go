@@ -19,11 +19,28 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	TaskService_CreateTask_FullMethodName = "/api.TaskService/CreateTask"
-	TaskService_ReadTask_FullMethodName   = "/api.TaskService/ReadTask"
-	TaskService_UpdateTask_FullMethodName = "/api.TaskService/UpdateTask"
-	TaskService_DeleteTask_FullMethodName = "/api.TaskService/DeleteTask"
-	TaskService_ListTask_FullMethodName   = "/api.TaskService/ListTask"
+	TaskService_CreateTask_FullMethodName          = "/api.TaskService/CreateTask"
+	TaskService_ReadTask_FullMethodName            = "/api.TaskService/ReadTask"
+	TaskService_UpdateTask_FullMethodName          = "/api.TaskService/UpdateTask"
+	TaskService_DeleteTask_FullMethodName          = "/api.TaskService/DeleteTask"
+	TaskService_ListTask_FullMethodName            = "/api.TaskService/ListTask"
+	TaskService_RestoreTask_FullMethodName         = "/api.TaskService/RestoreTask"
+	TaskService_ListDeletedTasks_FullMethodName    = "/api.TaskService/ListDeletedTasks"
+	TaskService_AddTaskLabels_FullMethodName       = "/api.TaskService/AddTaskLabels"
+	TaskService_RemoveTaskLabels_FullMethodName    = "/api.TaskService/RemoveTaskLabels"
+	TaskService_SearchTasks_FullMethodName         = "/api.TaskService/SearchTasks"
+	TaskService_GetTaskTree_FullMethodName         = "/api.TaskService/GetTaskTree"
+	TaskService_AddDependency_FullMethodName       = "/api.TaskService/AddDependency"
+	TaskService_RemoveDependency_FullMethodName    = "/api.TaskService/RemoveDependency"
+	TaskService_GetProjectTaskOrder_FullMethodName = "/api.TaskService/GetProjectTaskOrder"
+	TaskService_ListTaskRevisions_FullMethodName   = "/api.TaskService/ListTaskRevisions"
+	TaskService_GetTaskRevision_FullMethodName     = "/api.TaskService/GetTaskRevision"
+	TaskService_RevertTask_FullMethodName          = "/api.TaskService/RevertTask"
+	TaskService_ShareTask_FullMethodName           = "/api.TaskService/ShareTask"
+	TaskService_UnshareTask_FullMethodName         = "/api.TaskService/UnshareTask"
+	TaskService_MoveTask_FullMethodName            = "/api.TaskService/MoveTask"
+	TaskService_GetTaskStats_FullMethodName        = "/api.TaskService/GetTaskStats"
+	TaskService_CloneTask_FullMethodName           = "/api.TaskService/CloneTask"
 )
 
 // TaskServiceClient is the client API for TaskService service.
@@ -35,6 +52,25 @@ type TaskServiceClient interface {
 	UpdateTask(ctx context.Context, in *UpdateTaskRequest, opts ...grpc.CallOption) (*UpdateTaskResponse, error)
 	DeleteTask(ctx context.Context, in *DeleteTaskRequest, opts ...grpc.CallOption) (*DeleteTaskResponse, error)
 	ListTask(ctx context.Context, in *ListTaskRequest, opts ...grpc.CallOption) (TaskService_ListTaskClient, error)
+	RestoreTask(ctx context.Context, in *RestoreTaskRequest, opts ...grpc.CallOption) (*RestoreTaskResponse, error)
+	ListDeletedTasks(ctx context.Context, in *ListDeletedTasksRequest, opts ...grpc.CallOption) (TaskService_ListDeletedTasksClient, error)
+	AddTaskLabels(ctx context.Context, in *AddTaskLabelsRequest, opts ...grpc.CallOption) (*AddTaskLabelsResponse, error)
+	RemoveTaskLabels(ctx context.Context, in *RemoveTaskLabelsRequest, opts ...grpc.CallOption) (*RemoveTaskLabelsResponse, error)
+	SearchTasks(ctx context.Context, in *SearchTasksRequest, opts ...grpc.CallOption) (*SearchTasksResponse, error)
+	GetTaskTree(ctx context.Context, in *GetTaskTreeRequest, opts ...grpc.CallOption) (*GetTaskTreeResponse, error)
+	AddDependency(ctx context.Context, in *AddDependencyRequest, opts ...grpc.CallOption) (*AddDependencyResponse, error)
+	RemoveDependency(ctx context.Context, in *RemoveDependencyRequest, opts ...grpc.CallOption) (*RemoveDependencyResponse, error)
+	GetProjectTaskOrder(ctx context.Context, in *GetProjectTaskOrderRequest, opts ...grpc.CallOption) (*GetProjectTaskOrderResponse, error)
+	ListTaskRevisions(ctx context.Context, in *ListTaskRevisionsRequest, opts ...grpc.CallOption) (TaskService_ListTaskRevisionsClient, error)
+	GetTaskRevision(ctx context.Context, in *GetTaskRevisionRequest, opts ...grpc.CallOption) (*GetTaskRevisionResponse, error)
+	RevertTask(ctx context.Context, in *RevertTaskRequest, opts ...grpc.CallOption) (*RevertTaskResponse, error)
+	ShareTask(ctx context.Context, in *ShareTaskRequest, opts ...grpc.CallOption) (*ShareTaskResponse, error)
+	UnshareTask(ctx context.Context, in *UnshareTaskRequest, opts ...grpc.CallOption) (*UnshareTaskResponse, error)
+	MoveTask(ctx context.Context, in *MoveTaskRequest, opts ...grpc.CallOption) (*MoveTaskResponse, error)
+	GetTaskStats(ctx context.Context, in *GetTaskStatsRequest, opts ...grpc.CallOption) (*GetTaskStatsResponse, error)
+	// Copies a task and its subtasks. The copies start as STATUS_TODO and
+	// keep neither dependencies, recurrence, sharing nor history.
+	CloneTask(ctx context.Context, in *CloneTaskRequest, opts ...grpc.CallOption) (*CloneTaskResponse, error)
 }
 
 type taskServiceClient struct {
@@ -113,6 +149,205 @@ func (x *taskServiceListTaskClient) Recv() (*ListTaskResponse, error) {
 	return m, nil
 }
 
+func (c *taskServiceClient) RestoreTask(ctx context.Context, in *RestoreTaskRequest, opts ...grpc.CallOption) (*RestoreTaskResponse, error) {
+	out := new(RestoreTaskResponse)
+	err := c.cc.Invoke(ctx, TaskService_RestoreTask_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ListDeletedTasks(ctx context.Context, in *ListDeletedTasksRequest, opts ...grpc.CallOption) (TaskService_ListDeletedTasksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TaskService_ServiceDesc.Streams[1], TaskService_ListDeletedTasks_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taskServiceListDeletedTasksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TaskService_ListDeletedTasksClient interface {
+	Recv() (*ListDeletedTasksResponse, error)
+	grpc.ClientStream
+}
+
+type taskServiceListDeletedTasksClient struct {
+	grpc.ClientStream
+}
+
+func (x *taskServiceListDeletedTasksClient) Recv() (*ListDeletedTasksResponse, error) {
+	m := new(ListDeletedTasksResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *taskServiceClient) AddTaskLabels(ctx context.Context, in *AddTaskLabelsRequest, opts ...grpc.CallOption) (*AddTaskLabelsResponse, error) {
+	out := new(AddTaskLabelsResponse)
+	err := c.cc.Invoke(ctx, TaskService_AddTaskLabels_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) RemoveTaskLabels(ctx context.Context, in *RemoveTaskLabelsRequest, opts ...grpc.CallOption) (*RemoveTaskLabelsResponse, error) {
+	out := new(RemoveTaskLabelsResponse)
+	err := c.cc.Invoke(ctx, TaskService_RemoveTaskLabels_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) SearchTasks(ctx context.Context, in *SearchTasksRequest, opts ...grpc.CallOption) (*SearchTasksResponse, error) {
+	out := new(SearchTasksResponse)
+	err := c.cc.Invoke(ctx, TaskService_SearchTasks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) GetTaskTree(ctx context.Context, in *GetTaskTreeRequest, opts ...grpc.CallOption) (*GetTaskTreeResponse, error) {
+	out := new(GetTaskTreeResponse)
+	err := c.cc.Invoke(ctx, TaskService_GetTaskTree_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) AddDependency(ctx context.Context, in *AddDependencyRequest, opts ...grpc.CallOption) (*AddDependencyResponse, error) {
+	out := new(AddDependencyResponse)
+	err := c.cc.Invoke(ctx, TaskService_AddDependency_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) RemoveDependency(ctx context.Context, in *RemoveDependencyRequest, opts ...grpc.CallOption) (*RemoveDependencyResponse, error) {
+	out := new(RemoveDependencyResponse)
+	err := c.cc.Invoke(ctx, TaskService_RemoveDependency_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) GetProjectTaskOrder(ctx context.Context, in *GetProjectTaskOrderRequest, opts ...grpc.CallOption) (*GetProjectTaskOrderResponse, error) {
+	out := new(GetProjectTaskOrderResponse)
+	err := c.cc.Invoke(ctx, TaskService_GetProjectTaskOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ListTaskRevisions(ctx context.Context, in *ListTaskRevisionsRequest, opts ...grpc.CallOption) (TaskService_ListTaskRevisionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TaskService_ServiceDesc.Streams[2], TaskService_ListTaskRevisions_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taskServiceListTaskRevisionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TaskService_ListTaskRevisionsClient interface {
+	Recv() (*ListTaskRevisionsResponse, error)
+	grpc.ClientStream
+}
+
+type taskServiceListTaskRevisionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *taskServiceListTaskRevisionsClient) Recv() (*ListTaskRevisionsResponse, error) {
+	m := new(ListTaskRevisionsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *taskServiceClient) GetTaskRevision(ctx context.Context, in *GetTaskRevisionRequest, opts ...grpc.CallOption) (*GetTaskRevisionResponse, error) {
+	out := new(GetTaskRevisionResponse)
+	err := c.cc.Invoke(ctx, TaskService_GetTaskRevision_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) RevertTask(ctx context.Context, in *RevertTaskRequest, opts ...grpc.CallOption) (*RevertTaskResponse, error) {
+	out := new(RevertTaskResponse)
+	err := c.cc.Invoke(ctx, TaskService_RevertTask_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ShareTask(ctx context.Context, in *ShareTaskRequest, opts ...grpc.CallOption) (*ShareTaskResponse, error) {
+	out := new(ShareTaskResponse)
+	err := c.cc.Invoke(ctx, TaskService_ShareTask_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) UnshareTask(ctx context.Context, in *UnshareTaskRequest, opts ...grpc.CallOption) (*UnshareTaskResponse, error) {
+	out := new(UnshareTaskResponse)
+	err := c.cc.Invoke(ctx, TaskService_UnshareTask_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) MoveTask(ctx context.Context, in *MoveTaskRequest, opts ...grpc.CallOption) (*MoveTaskResponse, error) {
+	out := new(MoveTaskResponse)
+	err := c.cc.Invoke(ctx, TaskService_MoveTask_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) GetTaskStats(ctx context.Context, in *GetTaskStatsRequest, opts ...grpc.CallOption) (*GetTaskStatsResponse, error) {
+	out := new(GetTaskStatsResponse)
+	err := c.cc.Invoke(ctx, TaskService_GetTaskStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) CloneTask(ctx context.Context, in *CloneTaskRequest, opts ...grpc.CallOption) (*CloneTaskResponse, error) {
+	out := new(CloneTaskResponse)
+	err := c.cc.Invoke(ctx, TaskService_CloneTask_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TaskServiceServer is the server API for TaskService service.
 // All implementations should embed UnimplementedTaskServiceServer
 // for forward compatibility
@@ -122,6 +357,25 @@ type TaskServiceServer interface {
 	UpdateTask(context.Context, *UpdateTaskRequest) (*UpdateTaskResponse, error)
 	DeleteTask(context.Context, *DeleteTaskRequest) (*DeleteTaskResponse, error)
 	ListTask(*ListTaskRequest, TaskService_ListTaskServer) error
+	RestoreTask(context.Context, *RestoreTaskRequest) (*RestoreTaskResponse, error)
+	ListDeletedTasks(*ListDeletedTasksRequest, TaskService_ListDeletedTasksServer) error
+	AddTaskLabels(context.Context, *AddTaskLabelsRequest) (*AddTaskLabelsResponse, error)
+	RemoveTaskLabels(context.Context, *RemoveTaskLabelsRequest) (*RemoveTaskLabelsResponse, error)
+	SearchTasks(context.Context, *SearchTasksRequest) (*SearchTasksResponse, error)
+	GetTaskTree(context.Context, *GetTaskTreeRequest) (*GetTaskTreeResponse, error)
+	AddDependency(context.Context, *AddDependencyRequest) (*AddDependencyResponse, error)
+	RemoveDependency(context.Context, *RemoveDependencyRequest) (*RemoveDependencyResponse, error)
+	GetProjectTaskOrder(context.Context, *GetProjectTaskOrderRequest) (*GetProjectTaskOrderResponse, error)
+	ListTaskRevisions(*ListTaskRevisionsRequest, TaskService_ListTaskRevisionsServer) error
+	GetTaskRevision(context.Context, *GetTaskRevisionRequest) (*GetTaskRevisionResponse, error)
+	RevertTask(context.Context, *RevertTaskRequest) (*RevertTaskResponse, error)
+	ShareTask(context.Context, *ShareTaskRequest) (*ShareTaskResponse, error)
+	UnshareTask(context.Context, *UnshareTaskRequest) (*UnshareTaskResponse, error)
+	MoveTask(context.Context, *MoveTaskRequest) (*MoveTaskResponse, error)
+	GetTaskStats(context.Context, *GetTaskStatsRequest) (*GetTaskStatsResponse, error)
+	// Copies a task and its subtasks. The copies start as STATUS_TODO and
+	// keep neither dependencies, recurrence, sharing nor history.
+	CloneTask(context.Context, *CloneTaskRequest) (*CloneTaskResponse, error)
 }
 
 // UnimplementedTaskServiceServer should be embedded to have forward compatible implementations.
@@ -143,6 +397,57 @@ func (UnimplementedTaskServiceServer) DeleteTask(context.Context, *DeleteTaskReq
 func (UnimplementedTaskServiceServer) ListTask(*ListTaskRequest, TaskService_ListTaskServer) error {
 	return status.Errorf(codes.Unimplemented, "method ListTask not implemented")
 }
+func (UnimplementedTaskServiceServer) RestoreTask(context.Context, *RestoreTaskRequest) (*RestoreTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreTask not implemented")
+}
+func (UnimplementedTaskServiceServer) ListDeletedTasks(*ListDeletedTasksRequest, TaskService_ListDeletedTasksServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListDeletedTasks not implemented")
+}
+func (UnimplementedTaskServiceServer) AddTaskLabels(context.Context, *AddTaskLabelsRequest) (*AddTaskLabelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTaskLabels not implemented")
+}
+func (UnimplementedTaskServiceServer) RemoveTaskLabels(context.Context, *RemoveTaskLabelsRequest) (*RemoveTaskLabelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveTaskLabels not implemented")
+}
+func (UnimplementedTaskServiceServer) SearchTasks(context.Context, *SearchTasksRequest) (*SearchTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTasks not implemented")
+}
+func (UnimplementedTaskServiceServer) GetTaskTree(context.Context, *GetTaskTreeRequest) (*GetTaskTreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTaskTree not implemented")
+}
+func (UnimplementedTaskServiceServer) AddDependency(context.Context, *AddDependencyRequest) (*AddDependencyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddDependency not implemented")
+}
+func (UnimplementedTaskServiceServer) RemoveDependency(context.Context, *RemoveDependencyRequest) (*RemoveDependencyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveDependency not implemented")
+}
+func (UnimplementedTaskServiceServer) GetProjectTaskOrder(context.Context, *GetProjectTaskOrderRequest) (*GetProjectTaskOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProjectTaskOrder not implemented")
+}
+func (UnimplementedTaskServiceServer) ListTaskRevisions(*ListTaskRevisionsRequest, TaskService_ListTaskRevisionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListTaskRevisions not implemented")
+}
+func (UnimplementedTaskServiceServer) GetTaskRevision(context.Context, *GetTaskRevisionRequest) (*GetTaskRevisionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTaskRevision not implemented")
+}
+func (UnimplementedTaskServiceServer) RevertTask(context.Context, *RevertTaskRequest) (*RevertTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevertTask not implemented")
+}
+func (UnimplementedTaskServiceServer) ShareTask(context.Context, *ShareTaskRequest) (*ShareTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShareTask not implemented")
+}
+func (UnimplementedTaskServiceServer) UnshareTask(context.Context, *UnshareTaskRequest) (*UnshareTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnshareTask not implemented")
+}
+func (UnimplementedTaskServiceServer) MoveTask(context.Context, *MoveTaskRequest) (*MoveTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveTask not implemented")
+}
+func (UnimplementedTaskServiceServer) GetTaskStats(context.Context, *GetTaskStatsRequest) (*GetTaskStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTaskStats not implemented")
+}
+func (UnimplementedTaskServiceServer) CloneTask(context.Context, *CloneTaskRequest) (*CloneTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneTask not implemented")
+}
 
 // UnsafeTaskServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to TaskServiceServer will
@@ -248,6 +553,318 @@ func (x *taskServiceListTaskServer) Send(m *ListTaskResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _TaskService_RestoreTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).RestoreTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_RestoreTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).RestoreTask(ctx, req.(*RestoreTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_ListDeletedTasks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListDeletedTasksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaskServiceServer).ListDeletedTasks(m, &taskServiceListDeletedTasksServer{stream})
+}
+
+type TaskService_ListDeletedTasksServer interface {
+	Send(*ListDeletedTasksResponse) error
+	grpc.ServerStream
+}
+
+type taskServiceListDeletedTasksServer struct {
+	grpc.ServerStream
+}
+
+func (x *taskServiceListDeletedTasksServer) Send(m *ListDeletedTasksResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TaskService_AddTaskLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTaskLabelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).AddTaskLabels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_AddTaskLabels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).AddTaskLabels(ctx, req.(*AddTaskLabelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_RemoveTaskLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveTaskLabelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).RemoveTaskLabels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_RemoveTaskLabels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).RemoveTaskLabels(ctx, req.(*RemoveTaskLabelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_SearchTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).SearchTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_SearchTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).SearchTasks(ctx, req.(*SearchTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_GetTaskTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTaskTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).GetTaskTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_GetTaskTree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).GetTaskTree(ctx, req.(*GetTaskTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_AddDependency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddDependencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).AddDependency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_AddDependency_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).AddDependency(ctx, req.(*AddDependencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_RemoveDependency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveDependencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).RemoveDependency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_RemoveDependency_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).RemoveDependency(ctx, req.(*RemoveDependencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_GetProjectTaskOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProjectTaskOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).GetProjectTaskOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_GetProjectTaskOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).GetProjectTaskOrder(ctx, req.(*GetProjectTaskOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_ListTaskRevisions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListTaskRevisionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaskServiceServer).ListTaskRevisions(m, &taskServiceListTaskRevisionsServer{stream})
+}
+
+type TaskService_ListTaskRevisionsServer interface {
+	Send(*ListTaskRevisionsResponse) error
+	grpc.ServerStream
+}
+
+type taskServiceListTaskRevisionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *taskServiceListTaskRevisionsServer) Send(m *ListTaskRevisionsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TaskService_GetTaskRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTaskRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).GetTaskRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_GetTaskRevision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).GetTaskRevision(ctx, req.(*GetTaskRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_RevertTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevertTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).RevertTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_RevertTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).RevertTask(ctx, req.(*RevertTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_ShareTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).ShareTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_ShareTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).ShareTask(ctx, req.(*ShareTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_UnshareTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnshareTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).UnshareTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_UnshareTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).UnshareTask(ctx, req.(*UnshareTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_MoveTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).MoveTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_MoveTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).MoveTask(ctx, req.(*MoveTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_GetTaskStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTaskStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).GetTaskStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_GetTaskStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).GetTaskStats(ctx, req.(*GetTaskStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_CloneTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).CloneTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_CloneTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).CloneTask(ctx, req.(*CloneTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TaskService_ServiceDesc is the grpc.ServiceDesc for TaskService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -271,6 +888,66 @@ var TaskService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteTask",
 			Handler:    _TaskService_DeleteTask_Handler,
 		},
+		{
+			MethodName: "RestoreTask",
+			Handler:    _TaskService_RestoreTask_Handler,
+		},
+		{
+			MethodName: "AddTaskLabels",
+			Handler:    _TaskService_AddTaskLabels_Handler,
+		},
+		{
+			MethodName: "RemoveTaskLabels",
+			Handler:    _TaskService_RemoveTaskLabels_Handler,
+		},
+		{
+			MethodName: "SearchTasks",
+			Handler:    _TaskService_SearchTasks_Handler,
+		},
+		{
+			MethodName: "GetTaskTree",
+			Handler:    _TaskService_GetTaskTree_Handler,
+		},
+		{
+			MethodName: "AddDependency",
+			Handler:    _TaskService_AddDependency_Handler,
+		},
+		{
+			MethodName: "RemoveDependency",
+			Handler:    _TaskService_RemoveDependency_Handler,
+		},
+		{
+			MethodName: "GetProjectTaskOrder",
+			Handler:    _TaskService_GetProjectTaskOrder_Handler,
+		},
+		{
+			MethodName: "GetTaskRevision",
+			Handler:    _TaskService_GetTaskRevision_Handler,
+		},
+		{
+			MethodName: "RevertTask",
+			Handler:    _TaskService_RevertTask_Handler,
+		},
+		{
+			MethodName: "ShareTask",
+			Handler:    _TaskService_ShareTask_Handler,
+		},
+		{
+			MethodName: "UnshareTask",
+			Handler:    _TaskService_UnshareTask_Handler,
+		},
+		{
+			MethodName: "MoveTask",
+			Handler:    _TaskService_MoveTask_Handler,
+		},
+		{
+			MethodName: "GetTaskStats",
+			Handler:    _TaskService_GetTaskStats_Handler,
+		},
+		{
+			MethodName: "CloneTask",
+			Handler:    _TaskService_CloneTask_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -278,6 +955,16 @@ var TaskService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _TaskService_ListTask_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ListDeletedTasks",
+			Handler:       _TaskService_ListDeletedTasks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListTaskRevisions",
+			Handler:       _TaskService_ListTaskRevisions_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api/tasks.proto",
 }
@@ -0,0 +1,324 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: api/timetracking.proto
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	TimeTrackingService_StartTimer_FullMethodName      = "/api.TimeTrackingService/StartTimer"
+	TimeTrackingService_StopTimer_FullMethodName       = "/api.TimeTrackingService/StopTimer"
+	TimeTrackingService_AddTimeEntry_FullMethodName    = "/api.TimeTrackingService/AddTimeEntry"
+	TimeTrackingService_DeleteTimeEntry_FullMethodName = "/api.TimeTrackingService/DeleteTimeEntry"
+	TimeTrackingService_ListTimeEntries_FullMethodName = "/api.TimeTrackingService/ListTimeEntries"
+	TimeTrackingService_TimeReport_FullMethodName      = "/api.TimeTrackingService/TimeReport"
+)
+
+// TimeTrackingServiceClient is the client API for TimeTrackingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TimeTrackingServiceClient interface {
+	// Starts a timer on a task. A user can only have one running timer.
+	StartTimer(ctx context.Context, in *StartTimerRequest, opts ...grpc.CallOption) (*StartTimerResponse, error)
+	// Stops the running timer of the caller.
+	StopTimer(ctx context.Context, in *StopTimerRequest, opts ...grpc.CallOption) (*StopTimerResponse, error)
+	AddTimeEntry(ctx context.Context, in *AddTimeEntryRequest, opts ...grpc.CallOption) (*AddTimeEntryResponse, error)
+	DeleteTimeEntry(ctx context.Context, in *DeleteTimeEntryRequest, opts ...grpc.CallOption) (*DeleteTimeEntryResponse, error)
+	ListTimeEntries(ctx context.Context, in *ListTimeEntriesRequest, opts ...grpc.CallOption) (TimeTrackingService_ListTimeEntriesClient, error)
+	TimeReport(ctx context.Context, in *TimeReportRequest, opts ...grpc.CallOption) (*TimeReportResponse, error)
+}
+
+type timeTrackingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTimeTrackingServiceClient(cc grpc.ClientConnInterface) TimeTrackingServiceClient {
+	return &timeTrackingServiceClient{cc}
+}
+
+func (c *timeTrackingServiceClient) StartTimer(ctx context.Context, in *StartTimerRequest, opts ...grpc.CallOption) (*StartTimerResponse, error) {
+	out := new(StartTimerResponse)
+	err := c.cc.Invoke(ctx, TimeTrackingService_StartTimer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timeTrackingServiceClient) StopTimer(ctx context.Context, in *StopTimerRequest, opts ...grpc.CallOption) (*StopTimerResponse, error) {
+	out := new(StopTimerResponse)
+	err := c.cc.Invoke(ctx, TimeTrackingService_StopTimer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timeTrackingServiceClient) AddTimeEntry(ctx context.Context, in *AddTimeEntryRequest, opts ...grpc.CallOption) (*AddTimeEntryResponse, error) {
+	out := new(AddTimeEntryResponse)
+	err := c.cc.Invoke(ctx, TimeTrackingService_AddTimeEntry_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timeTrackingServiceClient) DeleteTimeEntry(ctx context.Context, in *DeleteTimeEntryRequest, opts ...grpc.CallOption) (*DeleteTimeEntryResponse, error) {
+	out := new(DeleteTimeEntryResponse)
+	err := c.cc.Invoke(ctx, TimeTrackingService_DeleteTimeEntry_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timeTrackingServiceClient) ListTimeEntries(ctx context.Context, in *ListTimeEntriesRequest, opts ...grpc.CallOption) (TimeTrackingService_ListTimeEntriesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TimeTrackingService_ServiceDesc.Streams[0], TimeTrackingService_ListTimeEntries_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &timeTrackingServiceListTimeEntriesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TimeTrackingService_ListTimeEntriesClient interface {
+	Recv() (*ListTimeEntriesResponse, error)
+	grpc.ClientStream
+}
+
+type timeTrackingServiceListTimeEntriesClient struct {
+	grpc.ClientStream
+}
+
+func (x *timeTrackingServiceListTimeEntriesClient) Recv() (*ListTimeEntriesResponse, error) {
+	m := new(ListTimeEntriesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *timeTrackingServiceClient) TimeReport(ctx context.Context, in *TimeReportRequest, opts ...grpc.CallOption) (*TimeReportResponse, error) {
+	out := new(TimeReportResponse)
+	err := c.cc.Invoke(ctx, TimeTrackingService_TimeReport_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TimeTrackingServiceServer is the server API for TimeTrackingService service.
+// All implementations should embed UnimplementedTimeTrackingServiceServer
+// for forward compatibility
+type TimeTrackingServiceServer interface {
+	// Starts a timer on a task. A user can only have one running timer.
+	StartTimer(context.Context, *StartTimerRequest) (*StartTimerResponse, error)
+	// Stops the running timer of the caller.
+	StopTimer(context.Context, *StopTimerRequest) (*StopTimerResponse, error)
+	AddTimeEntry(context.Context, *AddTimeEntryRequest) (*AddTimeEntryResponse, error)
+	DeleteTimeEntry(context.Context, *DeleteTimeEntryRequest) (*DeleteTimeEntryResponse, error)
+	ListTimeEntries(*ListTimeEntriesRequest, TimeTrackingService_ListTimeEntriesServer) error
+	TimeReport(context.Context, *TimeReportRequest) (*TimeReportResponse, error)
+}
+
+// UnimplementedTimeTrackingServiceServer should be embedded to have forward compatible implementations.
+type UnimplementedTimeTrackingServiceServer struct {
+}
+
+func (UnimplementedTimeTrackingServiceServer) StartTimer(context.Context, *StartTimerRequest) (*StartTimerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartTimer not implemented")
+}
+func (UnimplementedTimeTrackingServiceServer) StopTimer(context.Context, *StopTimerRequest) (*StopTimerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopTimer not implemented")
+}
+func (UnimplementedTimeTrackingServiceServer) AddTimeEntry(context.Context, *AddTimeEntryRequest) (*AddTimeEntryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTimeEntry not implemented")
+}
+func (UnimplementedTimeTrackingServiceServer) DeleteTimeEntry(context.Context, *DeleteTimeEntryRequest) (*DeleteTimeEntryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTimeEntry not implemented")
+}
+func (UnimplementedTimeTrackingServiceServer) ListTimeEntries(*ListTimeEntriesRequest, TimeTrackingService_ListTimeEntriesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListTimeEntries not implemented")
+}
+func (UnimplementedTimeTrackingServiceServer) TimeReport(context.Context, *TimeReportRequest) (*TimeReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TimeReport not implemented")
+}
+
+// UnsafeTimeTrackingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TimeTrackingServiceServer will
+// result in compilation errors.
+type UnsafeTimeTrackingServiceServer interface {
+	mustEmbedUnimplementedTimeTrackingServiceServer()
+}
+
+func RegisterTimeTrackingServiceServer(s grpc.ServiceRegistrar, srv TimeTrackingServiceServer) {
+	s.RegisterService(&TimeTrackingService_ServiceDesc, srv)
+}
+
+func _TimeTrackingService_StartTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartTimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimeTrackingServiceServer).StartTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimeTrackingService_StartTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimeTrackingServiceServer).StartTimer(ctx, req.(*StartTimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimeTrackingService_StopTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopTimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimeTrackingServiceServer).StopTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimeTrackingService_StopTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimeTrackingServiceServer).StopTimer(ctx, req.(*StopTimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimeTrackingService_AddTimeEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTimeEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimeTrackingServiceServer).AddTimeEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimeTrackingService_AddTimeEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimeTrackingServiceServer).AddTimeEntry(ctx, req.(*AddTimeEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimeTrackingService_DeleteTimeEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTimeEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimeTrackingServiceServer).DeleteTimeEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimeTrackingService_DeleteTimeEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimeTrackingServiceServer).DeleteTimeEntry(ctx, req.(*DeleteTimeEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimeTrackingService_ListTimeEntries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListTimeEntriesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TimeTrackingServiceServer).ListTimeEntries(m, &timeTrackingServiceListTimeEntriesServer{stream})
+}
+
+type TimeTrackingService_ListTimeEntriesServer interface {
+	Send(*ListTimeEntriesResponse) error
+	grpc.ServerStream
+}
+
+type timeTrackingServiceListTimeEntriesServer struct {
+	grpc.ServerStream
+}
+
+func (x *timeTrackingServiceListTimeEntriesServer) Send(m *ListTimeEntriesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TimeTrackingService_TimeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimeTrackingServiceServer).TimeReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimeTrackingService_TimeReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimeTrackingServiceServer).TimeReport(ctx, req.(*TimeReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TimeTrackingService_ServiceDesc is the grpc.ServiceDesc for TimeTrackingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TimeTrackingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.TimeTrackingService",
+	HandlerType: (*TimeTrackingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "StartTimer",
+			Handler:    _TimeTrackingService_StartTimer_Handler,
+		},
+		{
+			MethodName: "StopTimer",
+			Handler:    _TimeTrackingService_StopTimer_Handler,
+		},
+		{
+			MethodName: "AddTimeEntry",
+			Handler:    _TimeTrackingService_AddTimeEntry_Handler,
+		},
+		{
+			MethodName: "DeleteTimeEntry",
+			Handler:    _TimeTrackingService_DeleteTimeEntry_Handler,
+		},
+		{
+			MethodName: "TimeReport",
+			Handler:    _TimeTrackingService_TimeReport_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListTimeEntries",
+			Handler:       _TimeTrackingService_ListTimeEntries_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/timetracking.proto",
+}
@@ -4,6 +4,7 @@ go 1.20
 
 require (
 	github.com/joho/godotenv v1.5.1
+	github.com/teambition/rrule-go v1.8.2
 	go.mongodb.org/mongo-driver v1.11.2
 	google.golang.org/grpc v1.53.0
 	google.golang.org/protobuf v1.29.1
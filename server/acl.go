@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type shareEntry struct {
+	UserID string        `bson:"user_id"`
+	Role   api.ShareRole `bson:"role"`
+}
+
+func getACLGRPC(list []shareEntry) []*api.ShareEntry {
+	var res []*api.ShareEntry
+	for _, e := range list {
+		res = append(res, &api.ShareEntry{UserId: e.UserID, Role: e.Role})
+	}
+	return res
+}
+
+// taskRole returns the highest role actor has on data. The owner and the
+// assignee are editors, and tasks without an owner are open to everyone.
+func taskRole(data *task, actor string) api.ShareRole {
+	if data.OwnerID == "" {
+		return api.ShareRole_SHARE_ROLE_EDITOR
+	}
+	if actor == "" {
+		return api.ShareRole_SHARE_ROLE_UNSPECIFIED
+	}
+	if actor == data.OwnerID || actor == data.AssigneeID {
+		return api.ShareRole_SHARE_ROLE_EDITOR
+	}
+	for _, e := range data.ACL {
+		if e.UserID == actor {
+			return e.Role
+		}
+	}
+	return api.ShareRole_SHARE_ROLE_UNSPECIFIED
+}
+
+// checkAccess fails with PermissionDenied unless the caller has at least
+// role on data.
+func checkAccess(ctx context.Context, data *task, role api.ShareRole) error {
+	if taskRole(data, actorFromContext(ctx)) >= role {
+		return nil
+	}
+	if role == api.ShareRole_SHARE_ROLE_EDITOR {
+		return status.Errorf(
+			codes.PermissionDenied,
+			"[ERROR] no permission to change task %s", data.ID.Hex(),
+		)
+	}
+	return status.Errorf(
+		codes.PermissionDenied,
+		"[ERROR] no permission to read task %s", data.ID.Hex(),
+	)
+}
+
+// checkAssignee fails with PermissionDenied unless the caller may make
+// assigneeID the assignee of data. The assignee is an editor, so only the
+// owner can hand a task to someone else; an assignee can only unassign
+// themselves.
+func checkAssignee(ctx context.Context, data *task, assigneeID string) error {
+	if assigneeID == data.AssigneeID || data.OwnerID == "" {
+		return nil
+	}
+	actor := actorFromContext(ctx)
+	if actor == data.OwnerID || (assigneeID == "" && actor == data.AssigneeID) {
+		return nil
+	}
+	return status.Errorf(
+		codes.PermissionDenied,
+		"[ERROR] only the owner can change the assignee of task %s", data.ID.Hex(),
+	)
+}
+
+// findTaskWithAccess loads task id, including a task in the trash, and checks
+// that the caller has at least role on it.
+func findTaskWithAccess(ctx context.Context, id primitive.ObjectID, role api.ShareRole) (*task, error) {
+	data, err := findTask(ctx, bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccess(ctx, data, role); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// checkOwner fails with PermissionDenied unless the caller owns the kind
+// object id. Objects created without a caller are open to everyone.
+func checkOwner(ctx context.Context, ownerID, kind string, id primitive.ObjectID) error {
+	if ownerID == "" || ownerID == actorFromContext(ctx) {
+		return nil
+	}
+	return status.Errorf(
+		codes.PermissionDenied,
+		"[ERROR] only the owner can change %s %s", kind, id.Hex(),
+	)
+}
+
+// ownedFilter restricts filter to the objects the caller owns, along with
+// those created without a caller.
+func ownedFilter(ctx context.Context, filter bson.M) bson.M {
+	owned := bson.A{bson.M{"owner_id": bson.M{"$exists": false}}}
+	if actor := actorFromContext(ctx); actor != "" {
+		owned = append(owned, bson.M{"owner_id": actor})
+	}
+	return bson.M{"$and": bson.A{filter, bson.M{"$or": owned}}}
+}
+
+// visibleFilter restricts filter to the tasks the caller can read.
+func visibleFilter(ctx context.Context, filter bson.M) bson.M {
+	actor := actorFromContext(ctx)
+	visible := bson.A{bson.M{"owner_id": bson.M{"$exists": false}}}
+	if actor != "" {
+		visible = append(visible,
+			bson.M{"owner_id": actor},
+			bson.M{"assignee_id": actor},
+			bson.M{"acl.user_id": actor},
+		)
+	}
+	return bson.M{"$and": bson.A{filter, bson.M{"$or": visible}}}
+}
+
+func (*server) ShareTask(ctx context.Context, req *api.ShareTaskRequest) (*api.ShareTaskResponse, error) {
+
+	log.Println("[INFO] share task")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if strings.TrimSpace(req.GetUserId()) == "" {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] user ID must not be empty",
+		)
+	}
+	role := req.GetRole()
+	if role != api.ShareRole_SHARE_ROLE_VIEWER && role != api.ShareRole_SHARE_ROLE_EDITOR {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] unknown share role: %v", role,
+		)
+	}
+
+	var res *api.ShareTaskResponse
+	err := inTransaction(ctx, func(ctx context.Context) error {
+		data, err := changeACL(ctx, req.GetTaskId(), func(data *task) error {
+			if req.GetUserId() == data.OwnerID {
+				return status.Errorf(
+					codes.InvalidArgument,
+					"[ERROR] the owner cannot be given a share role",
+				)
+			}
+			for i := range data.ACL {
+				if data.ACL[i].UserID == req.GetUserId() {
+					data.ACL[i].Role = role
+					return nil
+				}
+			}
+			data.ACL = append(data.ACL, shareEntry{UserID: req.GetUserId(), Role: role})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		res = &api.ShareTaskResponse{Task: getTaskGRPC(data)}
+		return nil
+	})
+	return res, err
+}
+
+func (*server) UnshareTask(ctx context.Context, req *api.UnshareTaskRequest) (*api.UnshareTaskResponse, error) {
+
+	log.Println("[INFO] unshare task")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var res *api.UnshareTaskResponse
+	err := inTransaction(ctx, func(ctx context.Context) error {
+		data, err := changeACL(ctx, req.GetTaskId(), func(data *task) error {
+			acl := data.ACL[:0]
+			for _, e := range data.ACL {
+				if e.UserID != req.GetUserId() {
+					acl = append(acl, e)
+				}
+			}
+			data.ACL = acl
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		res = &api.UnshareTaskResponse{Task: getTaskGRPC(data)}
+		return nil
+	})
+	return res, err
+}
+
+// changeACL applies change to the ACL of a task owned by the caller and
+// stores the result as a new revision.
+func changeACL(ctx context.Context, id string, change func(data *task) error) (*task, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse task ID",
+		)
+	}
+
+	filter := activeFilter(bson.M{"_id": oid})
+	data, err := findTask(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if data.OwnerID == "" {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			"[ERROR] task has no owner and is open to everyone",
+		)
+	}
+	if data.OwnerID != actorFromContext(ctx) {
+		return nil, status.Errorf(
+			codes.PermissionDenied,
+			"[ERROR] only the owner can share a task",
+		)
+	}
+	before := *data
+	before.ACL = append([]shareEntry(nil), data.ACL...)
+
+	if err := change(data); err != nil {
+		return nil, err
+	}
+	data.UpdateTime = time.Now().UTC()
+	data.Revision++
+	data.EventSeq++
+
+	if _, err := collection.ReplaceOne(ctx, filter, data); err != nil {
+		return nil, dbError(ctx, err, "cannot update object in MongoDB")
+	}
+	if err := recordTaskEvent(ctx, eventTaskUpdated, data); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, oid, &before, data); err != nil {
+		return nil, err
+	}
+	if err := recordRevision(ctx, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func actorContext(actor string) context.Context {
+	if actor == "" {
+		return context.Background()
+	}
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(userIDHeader, actor))
+}
+
+func TestCheckAssignee(t *testing.T) {
+	owned := &task{ID: primitive.NewObjectID(), OwnerID: "alice", AssigneeID: "bob"}
+	open := &task{ID: primitive.NewObjectID(), AssigneeID: "bob"}
+	tests := []struct {
+		data     *task
+		actor    string
+		assignee string
+		ok       bool
+	}{
+		{owned, "alice", "carol", true},
+		{owned, "alice", "", true},
+		{owned, "bob", "bob", true},
+		{owned, "bob", "", true},
+		{owned, "bob", "carol", false},
+		{owned, "bob", "mallory", false},
+		{owned, "carol", "", false},
+		{owned, "carol", "carol", false},
+		{owned, "", "carol", false},
+		{open, "", "carol", true},
+		{open, "carol", "", true},
+	}
+	for _, tt := range tests {
+		err := checkAssignee(actorContext(tt.actor), tt.data, tt.assignee)
+		if tt.ok && err != nil {
+			t.Errorf("%q assigning %q to a task of %q failed: %v", tt.actor, tt.assignee, tt.data.OwnerID, err)
+		}
+		if !tt.ok && status.Code(err) != codes.PermissionDenied {
+			t.Errorf("%q assigning %q to a task of %q: error = %v, want PermissionDenied", tt.actor, tt.assignee, tt.data.OwnerID, err)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// sniffLen is how many leading bytes content type detection looks at.
+	sniffLen = 512
+	// downloadChunkSize is the size of the chunks DownloadAttachment sends.
+	downloadChunkSize = 64 << 10
+)
+
+var (
+	defaultAttachmentMaxSize int64 = 10 << 20
+	attachmentMaxSize              = defaultAttachmentMaxSize
+
+	attachmentCollection *mongo.Collection
+	attachmentBlobs      blobStore
+)
+
+type attachment struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	TaskID      primitive.ObjectID `bson:"task_id"`
+	Filename    string             `bson:"filename"`
+	ContentType string             `bson:"content_type"`
+	Size        int64              `bson:"size"`
+	SHA256      string             `bson:"sha256"`
+	Author      string             `bson:"author"`
+	CreateTime  time.Time          `bson:"create_time"`
+	// DeleteTime marks an attachment of a deleted task whose content is
+	// waiting for sweepAttachments.
+	DeleteTime *time.Time `bson:"delete_time,omitempty"`
+}
+
+func getAttachmentGRPC(data *attachment) *api.Attachment {
+	return &api.Attachment{
+		Id:          data.ID.Hex(),
+		TaskId:      data.TaskID.Hex(),
+		Filename:    data.Filename,
+		ContentType: data.ContentType,
+		Size:        data.Size,
+		Sha256:      data.SHA256,
+		Author:      data.Author,
+		CreateTime:  timeToProto(&data.CreateTime),
+	}
+}
+
+// newAttachment validates the info message that starts an upload.
+func newAttachment(ctx context.Context, info *api.Attachment) (*attachment, error) {
+	if info == nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] upload must start with the attachment info",
+		)
+	}
+	taskID, err := primitive.ObjectIDFromHex(info.GetTaskId())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse task ID",
+		)
+	}
+	name := path.Base(strings.ReplaceAll(info.GetFilename(), "\\", "/"))
+	if strings.TrimSpace(name) == "" || name == "." || name == "/" {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] attachment filename must not be empty",
+		)
+	}
+	sum := strings.ToLower(info.GetSha256())
+	if b, err := hex.DecodeString(sum); err != nil || len(b) != sha256.Size {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] sha256 must be a hex-encoded SHA-256 checksum",
+		)
+	}
+
+	return &attachment{
+		ID:          primitive.NewObjectID(),
+		TaskID:      taskID,
+		Filename:    name,
+		ContentType: info.GetContentType(),
+		SHA256:      sum,
+		Author:      actorFromContext(ctx),
+		CreateTime:  time.Now().UTC(),
+	}, nil
+}
+
+// sniffContentType detects the type of content from its first bytes,
+// keeping the declared type when detection finds nothing specific.
+func sniffContentType(head []byte, declared string) string {
+	detected := http.DetectContentType(head)
+	if detected == "application/octet-stream" && declared != "" {
+		return declared
+	}
+	return detected
+}
+
+// receiveContent copies the chunks of an upload to w, counting and
+// hashing them and keeping the first bytes for type detection.
+func receiveContent(stream api.AttachmentService_UploadAttachmentServer, w io.Writer, h hash.Hash, data *attachment) ([]byte, error) {
+	var head []byte
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return head, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if req.GetInfo() != nil {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] attachment info must be sent only once",
+			)
+		}
+
+		chunk := req.GetChunk()
+		data.Size += int64(len(chunk))
+		if data.Size > attachmentMaxSize {
+			return nil, status.Errorf(
+				codes.ResourceExhausted,
+				"[ERROR] attachment is larger than %d bytes", attachmentMaxSize,
+			)
+		}
+		if n := sniffLen - len(head); n > 0 {
+			if n > len(chunk) {
+				n = len(chunk)
+			}
+			head = append(head, chunk[:n]...)
+		}
+		h.Write(chunk)
+		if _, err := w.Write(chunk); err != nil {
+			return nil, dbError(stream.Context(), err, "cannot store attachment")
+		}
+	}
+}
+
+func findAttachment(ctx context.Context, id string) (*attachment, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse attachment ID",
+		)
+	}
+
+	data := &attachment{}
+	filter := bson.M{"_id": oid, "delete_time": bson.M{"$exists": false}}
+	if err := attachmentCollection.FindOne(ctx, filter).Decode(data); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Errorf(
+				codes.NotFound,
+				"[ERROR] cannot find attachment with ID: %v", err,
+			)
+		}
+		return nil, dbError(ctx, err, "cannot read attachment from MongoDB")
+	}
+	return data, nil
+}
+
+// dropAttachments marks the attachments of permanently deleted tasks. It
+// runs in the transaction that deletes the tasks, so their content is only
+// removed by sweepAttachments once that transaction has committed.
+func dropAttachments(ctx context.Context, ids []interface{}) error {
+	update := bson.M{"$set": bson.M{"delete_time": time.Now().UTC()}}
+	if _, err := attachmentCollection.UpdateMany(ctx, bson.M{"task_id": bson.M{"$in": ids}}, update); err != nil {
+		return dbError(ctx, err, "cannot delete task attachments")
+	}
+	return nil
+}
+
+// sweepAttachments removes the content of the attachments marked by
+// dropAttachments, and then their records.
+func sweepAttachments(ctx context.Context) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cur, err := attachmentCollection.Find(ctx, bson.M{"delete_time": bson.M{"$exists": true}}, opts)
+	if err != nil {
+		log.Printf("[ERROR] cannot sweep attachments: %v\n", err)
+		return
+	}
+	var list []attachment
+	if err := cur.All(ctx, &list); err != nil {
+		log.Printf("[ERROR] cannot sweep attachments: %v\n", err)
+		return
+	}
+
+	for _, data := range list {
+		if err := attachmentBlobs.Delete(ctx, data.ID); err != nil {
+			log.Printf("[ERROR] cannot sweep attachments: %v\n", err)
+			return
+		}
+		if _, err := attachmentCollection.DeleteOne(ctx, bson.M{"_id": data.ID}); err != nil {
+			log.Printf("[ERROR] cannot sweep attachments: %v\n", err)
+			return
+		}
+	}
+	if len(list) > 0 {
+		log.Printf("[INFO] removed %d attachments of deleted tasks\n", len(list))
+	}
+}
+
+type attachmentServer struct {
+	api.AttachmentServiceServer
+}
+
+func (*attachmentServer) UploadAttachment(stream api.AttachmentService_UploadAttachmentServer) error {
+
+	log.Println("[INFO] upload attachment")
+
+	ctx := stream.Context()
+
+	req, err := stream.Recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] upload must start with the attachment info",
+			)
+		}
+		return err
+	}
+	data, err := newAttachment(ctx, req.GetInfo())
+	if err != nil {
+		return err
+	}
+
+	findCtx, cancel := withQueryTimeout(ctx)
+	t, err := findTask(findCtx, activeFilter(bson.M{"_id": data.TaskID}))
+	cancel()
+	if err != nil {
+		return err
+	}
+	if err := checkAccess(ctx, t, api.ShareRole_SHARE_ROLE_EDITOR); err != nil {
+		return err
+	}
+
+	w, err := attachmentBlobs.Create(ctx, data.ID, data.Filename)
+	if err != nil {
+		return dbError(ctx, err, "cannot store attachment")
+	}
+	h := sha256.New()
+	head, err := receiveContent(stream, w, h, data)
+	if err != nil {
+		w.Abort()
+		return err
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != data.SHA256 {
+		w.Abort()
+		return status.Errorf(
+			codes.DataLoss,
+			"[ERROR] checksum mismatch: received content has sha256 %s", sum,
+		)
+	}
+	data.ContentType = sniffContentType(head, data.ContentType)
+	if err := w.Close(); err != nil {
+		return dbError(ctx, err, "cannot store attachment")
+	}
+
+	ctx, cancel = withQueryTimeout(ctx)
+	defer cancel()
+	if _, err := attachmentCollection.InsertOne(ctx, data); err != nil {
+		attachmentBlobs.Delete(context.Background(), data.ID)
+		return dbError(ctx, err, "cannot create attachment in MongoDB")
+	}
+
+	return stream.SendAndClose(&api.UploadAttachmentResponse{
+		Attachment: getAttachmentGRPC(data),
+	})
+}
+
+func (*attachmentServer) DownloadAttachment(req *api.DownloadAttachmentRequest, stream api.AttachmentService_DownloadAttachmentServer) error {
+
+	log.Println("[INFO] download attachment")
+
+	ctx := stream.Context()
+
+	findCtx, cancel := withQueryTimeout(ctx)
+	data, err := findAttachment(findCtx, req.GetId())
+	if err == nil {
+		_, err = findTaskWithAccess(findCtx, data.TaskID, api.ShareRole_SHARE_ROLE_VIEWER)
+	}
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	r, err := attachmentBlobs.Open(ctx, data.ID)
+	if err != nil {
+		if errors.Is(err, errBlobNotFound) {
+			return status.Errorf(
+				codes.DataLoss,
+				"[ERROR] content of attachment %s is missing", req.GetId(),
+			)
+		}
+		return dbError(ctx, err, "cannot read attachment content")
+	}
+	defer r.Close()
+
+	if err := stream.Send(&api.DownloadAttachmentResponse{
+		Data: &api.DownloadAttachmentResponse_Info{Info: getAttachmentGRPC(data)},
+	}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, downloadChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if err := stream.Send(&api.DownloadAttachmentResponse{
+				Data: &api.DownloadAttachmentResponse_Chunk{Chunk: buf[:n]},
+			}); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return dbError(ctx, err, "cannot read attachment content")
+		}
+	}
+}
+
+func (*attachmentServer) ListAttachments(req *api.ListAttachmentsRequest, stream api.AttachmentService_ListAttachmentsServer) error {
+
+	log.Println("[INFO] stream list attachments")
+
+	taskID, err := primitive.ObjectIDFromHex(req.GetTaskId())
+	if err != nil {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse task ID",
+		)
+	}
+
+	findCtx, cancel := withQueryTimeout(stream.Context())
+	_, err = findTaskWithAccess(findCtx, taskID, api.ShareRole_SHARE_ROLE_VIEWER)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "create_time", Value: 1}})
+	filter := bson.M{"task_id": taskID, "delete_time": bson.M{"$exists": false}}
+	return streamDocs(stream.Context(), attachmentCollection, filter, opts, func(data *attachment) error {
+		return stream.Send(&api.ListAttachmentsResponse{
+			Attachment: getAttachmentGRPC(data),
+		})
+	})
+}
+
+func (*attachmentServer) DeleteAttachment(ctx context.Context, req *api.DeleteAttachmentRequest) (*api.DeleteAttachmentResponse, error) {
+
+	log.Println("[INFO] delete attachment")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data, err := findAttachment(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := findTaskWithAccess(ctx, data.TaskID, api.ShareRole_SHARE_ROLE_EDITOR); err != nil {
+		return nil, err
+	}
+	if _, err := attachmentCollection.DeleteOne(ctx, bson.M{"_id": data.ID}); err != nil {
+		return nil, dbError(ctx, err, "cannot delete attachment in MongoDB")
+	}
+	if err := attachmentBlobs.Delete(ctx, data.ID); err != nil {
+		return nil, dbError(ctx, err, "cannot delete attachment content")
+	}
+
+	return &api.DeleteAttachmentResponse{
+		Id: req.GetId(),
+	}, nil
+}
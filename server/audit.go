@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var auditCollection *mongo.Collection
+
+type auditEvent struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	Actor       string             `bson:"actor"`
+	Time        time.Time          `bson:"time"`
+	Method      string             `bson:"method"`
+	TaskID      primitive.ObjectID `bson:"task_id"`
+	Before      *task              `bson:"before,omitempty"`
+	After       *task              `bson:"after,omitempty"`
+	PeerAddress string             `bson:"peer_address,omitempty"`
+	RequestID   string             `bson:"request_id"`
+}
+
+func getAuditEventGRPC(data *auditEvent) *api.AuditEvent {
+	e := &api.AuditEvent{
+		Id:          data.ID.Hex(),
+		Actor:       data.Actor,
+		Time:        timeToProto(&data.Time),
+		Method:      data.Method,
+		TaskId:      data.TaskID.Hex(),
+		PeerAddress: data.PeerAddress,
+		RequestId:   data.RequestID,
+	}
+	if data.Before != nil {
+		e.Before = getTaskGRPC(data.Before)
+	}
+	if data.After != nil {
+		e.After = getTaskGRPC(data.After)
+	}
+	return e
+}
+
+// recordAudit stores an audit entry for a call that changed a task from
+// before to after. Either snapshot may be nil. It must run in the same
+// transaction as the change.
+func recordAudit(ctx context.Context, taskID primitive.ObjectID, before, after *task) error {
+	method, _ := grpc.Method(ctx)
+	_, err := auditCollection.InsertOne(ctx, auditEvent{
+		ID:          primitive.NewObjectID(),
+		Actor:       actorFromContext(ctx),
+		Time:        time.Now().UTC(),
+		Method:      method,
+		TaskID:      taskID,
+		Before:      before,
+		After:       after,
+		PeerAddress: peerAddress(ctx),
+		RequestID:   requestIDFromContext(ctx),
+	})
+	if err != nil {
+		return dbError(ctx, err, "cannot write audit entry")
+	}
+	return nil
+}
+
+// auditAccess remembers which tasks the caller can read while listing audit
+// events.
+type auditAccess map[primitive.ObjectID]bool
+
+// check reports whether the caller can read the task of e. Events of tasks
+// that were deleted for good are checked against their last snapshot.
+func (a auditAccess) check(ctx context.Context, e *auditEvent) (bool, error) {
+	if ok, seen := a[e.TaskID]; seen {
+		return ok, nil
+	}
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data, err := findTask(ctx, bson.M{"_id": e.TaskID})
+	if status.Code(err) == codes.NotFound {
+		data, err = e.After, nil
+		if data == nil {
+			data = e.Before
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	ok := data != nil && taskRole(data, actorFromContext(ctx)) >= api.ShareRole_SHARE_ROLE_VIEWER
+	a[e.TaskID] = ok
+	return ok, nil
+}
+
+type auditServer struct {
+	api.AuditServiceServer
+}
+
+func (*auditServer) ListAuditEvents(req *api.ListAuditEventsRequest, stream api.AuditService_ListAuditEventsServer) error {
+
+	log.Println("[INFO] stream list audit events")
+
+	filter := bson.M{}
+	if req.GetActor() != "" {
+		filter["actor"] = req.GetActor()
+	}
+	if req.GetTaskId() != "" {
+		oid, err := primitive.ObjectIDFromHex(req.GetTaskId())
+		if err != nil {
+			return status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] cannot parse task ID",
+			)
+		}
+		findCtx, cancel := withQueryTimeout(stream.Context())
+		_, err = findTaskWithAccess(findCtx, oid, api.ShareRole_SHARE_ROLE_VIEWER)
+		cancel()
+		if err != nil {
+			return err
+		}
+		filter["task_id"] = oid
+	}
+	period := bson.M{}
+	if req.GetStartTime() != nil {
+		period["$gte"] = req.GetStartTime().AsTime()
+	}
+	if req.GetEndTime() != nil {
+		period["$lt"] = req.GetEndTime().AsTime()
+	}
+	if len(period) > 0 {
+		filter["time"] = period
+	}
+
+	readable := auditAccess{}
+	opts := options.Find().SetSort(bson.D{{Key: "time", Value: 1}, {Key: "_id", Value: 1}})
+	return streamDocs(stream.Context(), auditCollection, filter, opts, func(data *auditEvent) error {
+		ok, err := readable.check(stream.Context(), data)
+		if err != nil || !ok {
+			return err
+		}
+		return stream.Send(&api.ListAuditEventsResponse{
+			Event: getAuditEventGRPC(data),
+		})
+	})
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+)
+
+var errBlobNotFound = errors.New("blob not found")
+
+// blobStore keeps the content of attachments.
+type blobStore interface {
+	// Create starts writing a new blob. The blob becomes readable once the
+	// writer is closed; Abort discards everything written so far.
+	Create(ctx context.Context, id primitive.ObjectID, name string) (blobWriter, error)
+	// Open returns errBlobNotFound for a missing blob.
+	Open(ctx context.Context, id primitive.ObjectID) (io.ReadCloser, error)
+	// Delete succeeds for a missing blob.
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type blobWriter interface {
+	io.WriteCloser
+	Abort() error
+}
+
+// gridFSStore keeps blobs in MongoDB GridFS.
+type gridFSStore struct {
+	bucket *gridfs.Bucket
+}
+
+func newGridFSStore(db *mongo.Database) (*gridFSStore, error) {
+	bucket, err := gridfs.NewBucket(db)
+	if err != nil {
+		return nil, err
+	}
+	return &gridFSStore{bucket: bucket}, nil
+}
+
+func (s *gridFSStore) Create(ctx context.Context, id primitive.ObjectID, name string) (blobWriter, error) {
+	w, err := s.bucket.OpenUploadStreamWithID(id, name)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := w.SetWriteDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+func (s *gridFSStore) Open(ctx context.Context, id primitive.ObjectID) (io.ReadCloser, error) {
+	r, err := s.bucket.OpenDownloadStream(id)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return nil, errBlobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := r.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (s *gridFSStore) Delete(ctx context.Context, id primitive.ObjectID) error {
+	if err := s.bucket.DeleteContext(ctx, id); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+		return err
+	}
+	return nil
+}
+
+// fileStore keeps blobs as files in a local directory.
+type fileStore struct {
+	dir string
+}
+
+func newFileStore(dir string) (*fileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &fileStore{dir: dir}, nil
+}
+
+func (s *fileStore) path(id primitive.ObjectID) string {
+	return filepath.Join(s.dir, id.Hex())
+}
+
+func (s *fileStore) Create(_ context.Context, id primitive.ObjectID, _ string) (blobWriter, error) {
+	f, err := os.CreateTemp(s.dir, id.Hex()+"-*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &fileWriter{File: f, path: s.path(id)}, nil
+}
+
+func (s *fileStore) Open(_ context.Context, id primitive.ObjectID) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errBlobNotFound
+	}
+	return f, err
+}
+
+func (s *fileStore) Delete(_ context.Context, id primitive.ObjectID) error {
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// fileWriter writes a blob to a temporary file and moves it into place on
+// Close, so readers never see a partial blob.
+type fileWriter struct {
+	*os.File
+	path string
+}
+
+func (w *fileWriter) Close() error {
+	if err := w.File.Sync(); err != nil {
+		w.Abort()
+		return err
+	}
+	if err := w.File.Close(); err != nil {
+		os.Remove(w.File.Name())
+		return err
+	}
+	return os.Rename(w.File.Name(), w.path)
+}
+
+func (w *fileWriter) Abort() error {
+	w.File.Close()
+	return os.Remove(w.File.Name())
+}
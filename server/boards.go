@@ -0,0 +1,370 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var boardCollection *mongo.Collection
+
+type board struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Name      string             `bson:"name"`
+	ProjectID primitive.ObjectID `bson:"project_id"`
+	Columns   []boardColumn      `bson:"columns"`
+	OwnerID   string             `bson:"owner_id,omitempty"`
+}
+
+type boardColumn struct {
+	Name     string       `bson:"name"`
+	Statuses []api.Status `bson:"statuses"`
+	WIPLimit int32        `bson:"wip_limit,omitempty"`
+}
+
+// column returns the column tasks with status st are shown in, or nil.
+func (b *board) column(st api.Status) *boardColumn {
+	for i := range b.Columns {
+		for _, s := range b.Columns[i].Statuses {
+			if s == st {
+				return &b.Columns[i]
+			}
+		}
+	}
+	return nil
+}
+
+func getBoardColumnGRPC(c *boardColumn) *api.BoardColumn {
+	return &api.BoardColumn{
+		Name:     c.Name,
+		Statuses: c.Statuses,
+		WipLimit: c.WIPLimit,
+	}
+}
+
+func getBoardGRPC(data *board) *api.Board {
+	res := &api.Board{
+		Id:        data.ID.Hex(),
+		Name:      data.Name,
+		ProjectId: data.ProjectID.Hex(),
+		OwnerId:   data.OwnerID,
+	}
+	for i := range data.Columns {
+		res.Columns = append(res.Columns, getBoardColumnGRPC(&data.Columns[i]))
+	}
+	return res
+}
+
+// boardFromGRPC validates a board sent by a client and converts it. Every
+// status may appear in at most one column so that a task is always shown
+// in a single place.
+func boardFromGRPC(ctx context.Context, b *api.Board) (*board, error) {
+	if strings.TrimSpace(b.GetName()) == "" {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] board name must not be empty",
+		)
+	}
+	if len(b.GetColumns()) == 0 {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] board must have at least one column",
+		)
+	}
+	projectID, err := taskProject(ctx, b.GetProjectId())
+	if err != nil {
+		return nil, err
+	}
+
+	data := &board{
+		Name:      b.GetName(),
+		ProjectID: projectID,
+	}
+	seen := make(map[api.Status]bool)
+	for _, c := range b.GetColumns() {
+		if strings.TrimSpace(c.GetName()) == "" {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] column name must not be empty",
+			)
+		}
+		if len(c.GetStatuses()) == 0 {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] column %q has no statuses", c.GetName(),
+			)
+		}
+		if c.GetWipLimit() < 0 {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] column %q has a negative WIP limit", c.GetName(),
+			)
+		}
+		for _, s := range c.GetStatuses() {
+			if !validStatus(s) {
+				return nil, status.Errorf(
+					codes.InvalidArgument,
+					"[ERROR] unknown status: %v", s,
+				)
+			}
+			if seen[s] {
+				return nil, status.Errorf(
+					codes.InvalidArgument,
+					"[ERROR] status %v appears in more than one column", s,
+				)
+			}
+			seen[s] = true
+		}
+		data.Columns = append(data.Columns, boardColumn{
+			Name:     c.GetName(),
+			Statuses: c.GetStatuses(),
+			WIPLimit: c.GetWipLimit(),
+		})
+	}
+	return data, nil
+}
+
+func findBoard(ctx context.Context, id string) (*board, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse ID",
+		)
+	}
+
+	data := &board{}
+	if err := boardCollection.FindOne(ctx, bson.M{"_id": oid}).Decode(data); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Errorf(
+				codes.NotFound,
+				"[ERROR] cannot find board with ID: %v", err,
+			)
+		}
+		return nil, dbError(ctx, err, "cannot read board from MongoDB")
+	}
+	return data, nil
+}
+
+// checkWIPLimits fails with FailedPrecondition when data, changed from
+// before, enters a board column that already holds as many tasks as its
+// work-in-progress limit allows. Moves within a column are always allowed.
+// It takes a lock per column so that concurrent moves into the same column
+// cannot both see the last free place.
+func checkWIPLimits(ctx context.Context, before, data *task) error {
+	if before.Status == data.Status && before.ProjectID == data.ProjectID {
+		return nil
+	}
+
+	cur, err := boardCollection.Find(ctx, bson.M{
+		"project_id":       data.ProjectID,
+		"columns.statuses": data.Status,
+	})
+	if err != nil {
+		return dbError(ctx, err, "cannot read boards from MongoDB")
+	}
+	var boards []board
+	if err := cur.All(ctx, &boards); err != nil {
+		return dbError(ctx, err, "cannot read boards from MongoDB")
+	}
+
+	for i := range boards {
+		col := boards[i].column(data.Status)
+		if col == nil || col.WIPLimit == 0 {
+			continue
+		}
+		if before.ProjectID == data.ProjectID && boards[i].column(before.Status) == col {
+			continue
+		}
+		if err := takeLock(ctx, "wip:"+boards[i].ID.Hex()+":"+col.Name); err != nil {
+			return err
+		}
+		n, err := collection.CountDocuments(ctx, activeFilter(bson.M{
+			"project_id": data.ProjectID,
+			"status":     bson.M{"$in": col.Statuses},
+			"_id":        bson.M{"$ne": data.ID},
+		}))
+		if err != nil {
+			return dbError(ctx, err, "cannot count column tasks")
+		}
+		if n >= int64(col.WIPLimit) {
+			return status.Errorf(
+				codes.FailedPrecondition,
+				"[ERROR] column %q of board %q is at its WIP limit of %d",
+				col.Name, boards[i].Name, col.WIPLimit,
+			)
+		}
+	}
+	return nil
+}
+
+// dropBoards removes the boards of a deleted project.
+func dropBoards(ctx context.Context, projectID primitive.ObjectID) error {
+	if _, err := boardCollection.DeleteMany(ctx, bson.M{"project_id": projectID}); err != nil {
+		return dbError(ctx, err, "cannot delete project boards")
+	}
+	return nil
+}
+
+type boardServer struct {
+	api.BoardServiceServer
+}
+
+func (*boardServer) CreateBoard(ctx context.Context, req *api.CreateBoardRequest) (*api.CreateBoardResponse, error) {
+
+	log.Println("[INFO] create board")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data, err := boardFromGRPC(ctx, req.GetBoard())
+	if err != nil {
+		return nil, err
+	}
+	data.OwnerID = actorFromContext(ctx)
+
+	res, err := boardCollection.InsertOne(ctx, data)
+	if err != nil {
+		return nil, dbError(ctx, err, "cannot create board in MongoDB")
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, status.Errorf(
+			codes.Internal,
+			"[ERROR] Cannot convert to OID",
+		)
+	}
+
+	data.ID = oid
+	return &api.CreateBoardResponse{
+		Board: getBoardGRPC(data),
+	}, nil
+}
+
+func (*boardServer) GetBoard(ctx context.Context, req *api.GetBoardRequest) (*api.GetBoardResponse, error) {
+
+	log.Println("[INFO] get board")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data, err := findBoard(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &api.GetBoardResponse{Board: getBoardGRPC(data)}
+	columns := make(map[api.Status]*api.BoardColumnTasks)
+	var statuses bson.A
+	for i := range data.Columns {
+		c := &api.BoardColumnTasks{Column: res.Board.Columns[i]}
+		res.Columns = append(res.Columns, c)
+		for _, s := range data.Columns[i].Statuses {
+			columns[s] = c
+			statuses = append(statuses, s)
+		}
+	}
+
+	filter := visibleFilter(ctx, activeFilter(bson.M{
+		"project_id": data.ProjectID,
+		"status":     bson.M{"$in": statuses},
+	}))
+	opts := options.Find().SetSort(bson.D{{Key: "rank", Value: 1}, {Key: "_id", Value: 1}})
+	err = streamTasks(ctx, filter, opts, func(t *task) error {
+		c := columns[t.Status]
+		c.Tasks = append(c.Tasks, getTaskGRPC(t))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (*boardServer) UpdateBoard(ctx context.Context, req *api.UpdateBoardRequest) (*api.UpdateBoardResponse, error) {
+
+	log.Println("[INFO] update board")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	old, err := findBoard(ctx, req.GetBoard().GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOwner(ctx, old.OwnerID, "board", old.ID); err != nil {
+		return nil, err
+	}
+	data, err := boardFromGRPC(ctx, req.GetBoard())
+	if err != nil {
+		return nil, err
+	}
+	data.ID = old.ID
+	data.OwnerID = old.OwnerID
+
+	_, err = boardCollection.ReplaceOne(ctx, bson.M{"_id": data.ID}, data)
+	if err != nil {
+		return nil, dbError(ctx, err, "cannot update board in MongoDB")
+	}
+
+	return &api.UpdateBoardResponse{
+		Board: getBoardGRPC(data),
+	}, nil
+}
+
+func (*boardServer) DeleteBoard(ctx context.Context, req *api.DeleteBoardRequest) (*api.DeleteBoardResponse, error) {
+
+	log.Println("[INFO] delete board")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data, err := findBoard(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOwner(ctx, data.OwnerID, "board", data.ID); err != nil {
+		return nil, err
+	}
+	if _, err := boardCollection.DeleteOne(ctx, bson.M{"_id": data.ID}); err != nil {
+		return nil, dbError(ctx, err, "cannot delete board in MongoDB")
+	}
+
+	return &api.DeleteBoardResponse{
+		Id: req.GetId(),
+	}, nil
+}
+
+func (*boardServer) ListBoards(req *api.ListBoardsRequest, stream api.BoardService_ListBoardsServer) error {
+
+	log.Println("[INFO] stream list boards")
+
+	filter := bson.M{}
+	if req.GetProjectId() != "" {
+		oid, err := primitive.ObjectIDFromHex(req.GetProjectId())
+		if err != nil {
+			return status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] cannot parse project ID",
+			)
+		}
+		filter["project_id"] = oid
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+	return streamDocs(stream.Context(), boardCollection, filter, opts, func(data *board) error {
+		return stream.Send(&api.ListBoardsResponse{
+			Board: getBoardGRPC(data),
+		})
+	})
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var commentCollection *mongo.Collection
+
+type comment struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	TaskID     primitive.ObjectID `bson:"task_id"`
+	Author     string             `bson:"author"`
+	Text       string             `bson:"text"`
+	CreateTime time.Time          `bson:"create_time"`
+	UpdateTime *time.Time         `bson:"update_time,omitempty"`
+}
+
+func getCommentGRPC(data *comment) *api.Comment {
+	return &api.Comment{
+		Id:         data.ID.Hex(),
+		TaskId:     data.TaskID.Hex(),
+		Author:     data.Author,
+		Text:       data.Text,
+		CreateTime: timeToProto(&data.CreateTime),
+		UpdateTime: timeToProto(data.UpdateTime),
+	}
+}
+
+func validateCommentText(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] comment text must not be empty",
+		)
+	}
+	return nil
+}
+
+// findOwnComment loads a comment and checks that the caller wrote it and
+// can still read its task.
+func findOwnComment(ctx context.Context, id string) (*comment, error) {
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse comment ID",
+		)
+	}
+
+	data := &comment{}
+	if err := commentCollection.FindOne(ctx, bson.M{"_id": oid}).Decode(data); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Errorf(
+				codes.NotFound,
+				"[ERROR] cannot find comment with ID: %v", err,
+			)
+		}
+		return nil, dbError(ctx, err, "cannot read comment from MongoDB")
+	}
+	if data.Author != actor {
+		return nil, status.Errorf(
+			codes.PermissionDenied,
+			"[ERROR] only the author can change a comment",
+		)
+	}
+	// Authors who lost access to the task lose their comments with it.
+	if _, err := findTaskWithAccess(ctx, data.TaskID, api.ShareRole_SHARE_ROLE_VIEWER); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// dropComments removes the comments of permanently deleted tasks.
+func dropComments(ctx context.Context, ids []interface{}) error {
+	if _, err := commentCollection.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": ids}}); err != nil {
+		return dbError(ctx, err, "cannot delete task comments")
+	}
+	return nil
+}
+
+type commentServer struct {
+	api.CommentServiceServer
+}
+
+func (*commentServer) AddComment(ctx context.Context, req *api.AddCommentRequest) (*api.AddCommentResponse, error) {
+
+	log.Println("[INFO] add comment")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	taskID, err := primitive.ObjectIDFromHex(req.GetTaskId())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse task ID",
+		)
+	}
+	if err := validateCommentText(req.GetText()); err != nil {
+		return nil, err
+	}
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t, err := findTask(ctx, activeFilter(bson.M{"_id": taskID}))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccess(ctx, t, api.ShareRole_SHARE_ROLE_VIEWER); err != nil {
+		return nil, err
+	}
+
+	data := comment{
+		ID:         primitive.NewObjectID(),
+		TaskID:     taskID,
+		Author:     actor,
+		Text:       req.GetText(),
+		CreateTime: time.Now().UTC(),
+	}
+	if _, err := commentCollection.InsertOne(ctx, data); err != nil {
+		return nil, dbError(ctx, err, "cannot create comment in MongoDB")
+	}
+
+	return &api.AddCommentResponse{
+		Comment: getCommentGRPC(&data),
+	}, nil
+}
+
+func (*commentServer) UpdateComment(ctx context.Context, req *api.UpdateCommentRequest) (*api.UpdateCommentResponse, error) {
+
+	log.Println("[INFO] update comment")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if err := validateCommentText(req.GetText()); err != nil {
+		return nil, err
+	}
+	data, err := findOwnComment(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	data.Text = req.GetText()
+	data.UpdateTime = &now
+
+	update := bson.M{"$set": bson.M{"text": data.Text, "update_time": now}}
+	if _, err := commentCollection.UpdateOne(ctx, bson.M{"_id": data.ID}, update); err != nil {
+		return nil, dbError(ctx, err, "cannot update comment in MongoDB")
+	}
+
+	return &api.UpdateCommentResponse{
+		Comment: getCommentGRPC(data),
+	}, nil
+}
+
+func (*commentServer) DeleteComment(ctx context.Context, req *api.DeleteCommentRequest) (*api.DeleteCommentResponse, error) {
+
+	log.Println("[INFO] delete comment")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data, err := findOwnComment(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := commentCollection.DeleteOne(ctx, bson.M{"_id": data.ID}); err != nil {
+		return nil, dbError(ctx, err, "cannot delete comment in MongoDB")
+	}
+
+	return &api.DeleteCommentResponse{
+		Id: req.GetId(),
+	}, nil
+}
+
+func (*commentServer) ListComments(ctx context.Context, req *api.ListCommentsRequest) (*api.ListCommentsResponse, error) {
+
+	log.Println("[INFO] list comments")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	taskID, err := primitive.ObjectIDFromHex(req.GetTaskId())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse task ID",
+		)
+	}
+	limit, err := pageSize(req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	offset, err := decodeOffsetToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := findTaskWithAccess(ctx, taskID, api.ShareRole_SHARE_ROLE_VIEWER); err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "create_time", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip(offset).
+		SetLimit(limit + 1)
+
+	cur, err := commentCollection.Find(ctx, bson.M{"task_id": taskID}, opts)
+	if err != nil {
+		return nil, dbError(ctx, err, "cannot list comments in MongoDB")
+	}
+	var list []comment
+	if err := cur.All(ctx, &list); err != nil {
+		return nil, dbError(ctx, err, "cannot list comments in MongoDB")
+	}
+
+	res := &api.ListCommentsResponse{}
+	if int64(len(list)) > limit {
+		list = list[:limit]
+		res.NextPageToken = encodeOffsetToken(offset + limit)
+	}
+	for i := range list {
+		res.Comments = append(res.Comments, getCommentGRPC(&list[i]))
+	}
+	return res, nil
+}
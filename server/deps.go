@@ -0,0 +1,356 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// closedStatuses are the statuses in which a task no longer blocks others.
+var closedStatuses = bson.A{api.Status_STATUS_DONE, api.Status_STATUS_CANCELLED}
+
+func hexIDs(oids []primitive.ObjectID) []string {
+	if len(oids) == 0 {
+		return nil
+	}
+	res := make([]string, len(oids))
+	for i, oid := range oids {
+		res[i] = oid.Hex()
+	}
+	return res
+}
+
+// isBlocked reports whether one of the blockers of data is still open.
+// Blockers in the trash do not count.
+func isBlocked(ctx context.Context, data *task) (bool, error) {
+	if len(data.BlockedBy) == 0 {
+		return false, nil
+	}
+	n, err := collection.CountDocuments(ctx, activeFilter(bson.M{
+		"_id":    bson.M{"$in": data.BlockedBy},
+		"status": bson.M{"$nin": closedStatuses},
+	}))
+	if err != nil {
+		return false, dbError(ctx, err, "cannot count blockers")
+	}
+	return n > 0, nil
+}
+
+// refreshBlocked recomputes the stored blocked flag of task id from the
+// status of its blockers, storing an event when it flips.
+func refreshBlocked(ctx context.Context, id primitive.ObjectID) error {
+	data, err := findTask(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	blocked, err := isBlocked(ctx, data)
+	if err != nil {
+		return err
+	}
+	if blocked == data.Blocked {
+		return nil
+	}
+	data.Blocked = blocked
+	data.EventSeq++
+
+	update := bson.M{"$set": bson.M{"blocked": data.Blocked, "event_seq": data.EventSeq}}
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return dbError(ctx, err, "cannot update blocked flag")
+	}
+	return recordTaskEvent(ctx, eventTaskUpdated, data)
+}
+
+// refreshDependents recomputes the blocked flag of every task blocked by one
+// of ids, after those tasks changed status or moved in or out of the trash.
+func refreshDependents(ctx context.Context, ids ...primitive.ObjectID) error {
+	deps, err := collection.Distinct(ctx, "_id", bson.M{"blocked_by": bson.M{"$in": ids}})
+	if err != nil {
+		return dbError(ctx, err, "cannot find dependent tasks")
+	}
+	for _, d := range deps {
+		if err := refreshBlocked(ctx, d.(primitive.ObjectID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropDependencies removes permanently deleted tasks from blocked_by lists.
+func dropDependencies(ctx context.Context, ids []interface{}) error {
+	deps, err := collection.Distinct(ctx, "_id", bson.M{"blocked_by": bson.M{"$in": ids}})
+	if err != nil {
+		return dbError(ctx, err, "cannot find dependent tasks")
+	}
+	if len(deps) == 0 {
+		return nil
+	}
+	_, err = collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": deps}}, bson.M{"$pull": bson.M{"blocked_by": bson.M{"$in": ids}}})
+	if err != nil {
+		return dbError(ctx, err, "cannot remove dependencies")
+	}
+	for _, d := range deps {
+		if err := refreshBlocked(ctx, d.(primitive.ObjectID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseDependency(taskID, blockerID string) (primitive.ObjectID, primitive.ObjectID, error) {
+	oid, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return oid, oid, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse task ID",
+		)
+	}
+	blocker, err := primitive.ObjectIDFromHex(blockerID)
+	if err != nil {
+		return oid, blocker, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse blocker ID",
+		)
+	}
+	if oid == blocker {
+		return oid, blocker, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] task cannot block itself",
+		)
+	}
+	return oid, blocker, nil
+}
+
+func (*server) AddDependency(ctx context.Context, req *api.AddDependencyRequest) (*api.AddDependencyResponse, error) {
+
+	log.Println("[INFO] add dependency")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var res *api.AddDependencyResponse
+	err := inTransaction(ctx, func(ctx context.Context) error {
+		data, err := changeDependencies(ctx, req.GetTaskId(), req.GetBlockerId(), true)
+		if err != nil {
+			return err
+		}
+		res = &api.AddDependencyResponse{Task: getTaskGRPC(data)}
+		return nil
+	})
+	return res, err
+}
+
+func (*server) RemoveDependency(ctx context.Context, req *api.RemoveDependencyRequest) (*api.RemoveDependencyResponse, error) {
+
+	log.Println("[INFO] remove dependency")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var res *api.RemoveDependencyResponse
+	err := inTransaction(ctx, func(ctx context.Context) error {
+		data, err := changeDependencies(ctx, req.GetTaskId(), req.GetBlockerId(), false)
+		if err != nil {
+			return err
+		}
+		res = &api.RemoveDependencyResponse{Task: getTaskGRPC(data)}
+		return nil
+	})
+	return res, err
+}
+
+// changeDependencies adds blockerID to or removes it from the blockers of
+// task taskID, recording the change like an update.
+func changeDependencies(ctx context.Context, taskID, blockerID string, add bool) (*task, error) {
+	oid, blocker, err := parseDependency(taskID, blockerID)
+	if err != nil {
+		return nil, err
+	}
+	filter := activeFilter(bson.M{"_id": oid})
+	data, err := findTask(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccess(ctx, data, api.ShareRole_SHARE_ROLE_EDITOR); err != nil {
+		return nil, err
+	}
+
+	list := make([]primitive.ObjectID, 0, len(data.BlockedBy)+1)
+	for _, b := range data.BlockedBy {
+		if b != blocker {
+			list = append(list, b)
+		}
+	}
+	if add {
+		if err := checkDependencyCycle(ctx, oid, blocker, blockerID); err != nil {
+			return nil, err
+		}
+		list = append(list, blocker)
+	}
+	if len(list) == len(data.BlockedBy) {
+		return data, nil
+	}
+
+	before := *data
+	data.BlockedBy = list
+	if data.Blocked, err = isBlocked(ctx, data); err != nil {
+		return nil, err
+	}
+	data.UpdateTime = time.Now().UTC()
+	data.Revision++
+	data.EventSeq++
+
+	if _, err := collection.ReplaceOne(ctx, filter, data); err != nil {
+		return nil, dbError(ctx, err, "cannot update dependencies")
+	}
+	if err := recordTaskEvent(ctx, eventTaskUpdated, data); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, oid, &before, data); err != nil {
+		return nil, err
+	}
+	if err := recordRevision(ctx, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// checkDependencyCycle fails if task oid waiting on blocker would close a
+// cycle, which it does if the blocker already waits on the task. The check
+// takes the dependency lock so that concurrent additions cannot close a
+// cycle between them.
+func checkDependencyCycle(ctx context.Context, oid, blocker primitive.ObjectID, blockerID string) error {
+	if err := takeLock(ctx, "dependencies"); err != nil {
+		return err
+	}
+	cur, err := collection.Aggregate(ctx, bson.A{
+		bson.M{"$match": visibleFilter(ctx, activeFilter(bson.M{"_id": blocker}))},
+		bson.M{"$graphLookup": bson.M{
+			"from":             collection.Name(),
+			"startWith":        "$blocked_by",
+			"connectFromField": "blocked_by",
+			"connectToField":   "_id",
+			"as":               "blockers",
+		}},
+		bson.M{"$project": bson.M{"blockers._id": 1}},
+	})
+	if err != nil {
+		return dbError(ctx, err, "cannot read blocker task")
+	}
+	var res []struct {
+		Blockers []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		} `bson:"blockers"`
+	}
+	if err := cur.All(ctx, &res); err != nil {
+		return dbError(ctx, err, "cannot read blocker task")
+	}
+	if len(res) == 0 {
+		return status.Errorf(
+			codes.FailedPrecondition,
+			"[ERROR] blocker task %s does not exist", blockerID,
+		)
+	}
+	for _, b := range res[0].Blockers {
+		if b.ID == oid {
+			return status.Errorf(
+				codes.FailedPrecondition,
+				"[ERROR] dependency would create a cycle",
+			)
+		}
+	}
+	return nil
+}
+
+func (*server) GetProjectTaskOrder(ctx context.Context, req *api.GetProjectTaskOrderRequest) (*api.GetProjectTaskOrderResponse, error) {
+
+	log.Println("[INFO] get project task order")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	projectID, err := primitive.ObjectIDFromHex(req.GetProjectId())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse project ID",
+		)
+	}
+
+	var tasks []*task
+	err = streamTasks(ctx, visibleFilter(ctx, activeFilter(bson.M{"project_id": projectID})), nil, func(data *task) error {
+		tasks = append(tasks, data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := topoSort(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &api.GetProjectTaskOrderResponse{}
+	for _, data := range order {
+		res.Tasks = append(res.Tasks, getTaskGRPC(data))
+	}
+	return res, nil
+}
+
+// topoSort orders tasks so that every task comes after its blockers, using
+// Kahn's algorithm. Blockers outside the given set are ignored and ties are
+// broken by ID so the order is stable.
+func topoSort(tasks []*task) ([]*task, error) {
+	byID := make(map[primitive.ObjectID]*task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	indegree := make(map[primitive.ObjectID]int, len(tasks))
+	blocks := make(map[primitive.ObjectID][]*task, len(tasks))
+	for _, t := range tasks {
+		for _, b := range t.BlockedBy {
+			if _, ok := byID[b]; ok {
+				indegree[t.ID]++
+				blocks[b] = append(blocks[b], t)
+			}
+		}
+	}
+
+	var ready []*task
+	for _, t := range tasks {
+		if indegree[t.ID] == 0 {
+			ready = append(ready, t)
+		}
+	}
+
+	less := func(a, b *task) bool { return a.ID.Hex() < b.ID.Hex() }
+	order := make([]*task, 0, len(tasks))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return less(ready[i], ready[j]) })
+		t := ready[0]
+		ready = ready[1:]
+		order = append(order, t)
+		for _, d := range blocks[t.ID] {
+			indegree[d.ID]--
+			if indegree[d.ID] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+
+	if len(order) != len(tasks) {
+		return nil, status.Errorf(
+			codes.Internal,
+			"[ERROR] task dependencies contain a cycle",
+		)
+	}
+	return order, nil
+}
@@ -0,0 +1,507 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// This file implements the filter language of ListTaskRequest.filter, a
+// subset of https://google.aip.dev/160:
+//
+//	expression  = sequence {"AND" sequence}
+//	sequence    = factor {factor}
+//	factor      = term {"OR" term}
+//	term        = ["NOT" | "-"] simple
+//	simple      = restriction | "(" expression ")"
+//	restriction = field comparator value
+//
+// As in AIP-160, OR binds tighter than AND, and terms separated only by
+// whitespace are joined with AND.
+
+const (
+	maxFilterLength = 2048
+	maxFilterDepth  = 32
+)
+
+type filterKind int
+
+const (
+	filterString filterKind = iota
+	filterUser
+	filterID
+	filterBool
+	filterInt
+	filterTime
+	filterStatus
+	filterPriority
+	filterLabels
+)
+
+type filterField struct {
+	key  string
+	kind filterKind
+}
+
+// filterFields lists the Task fields a filter can refer to.
+var filterFields = map[string]filterField{
+	"id":           {"_id", filterID},
+	"name":         {"name", filterString},
+	"desc":         {"desc", filterString},
+	"done":         {"done", filterBool},
+	"blocked":      {"blocked", filterBool},
+	"priority":     {"priority", filterPriority},
+	"status":       {"status", filterStatus},
+	"due_time":     {"due_time", filterTime},
+	"labels":       {"labels", filterLabels},
+	"project_id":   {"project_id", filterID},
+	"parent_id":    {"parent_id", filterID},
+	"series_id":    {"series_id", filterID},
+	"recurrence":   {"recurrence", filterString},
+	"owner_id":     {"owner_id", filterUser},
+	"assignee_id":  {"assignee_id", filterUser},
+	"revision":     {"revision", filterInt},
+	"story_points": {"story_points", filterInt},
+}
+
+var filterOperators = map[string]string{
+	"=":  "$eq",
+	"!=": "$ne",
+	"<":  "$lt",
+	"<=": "$lte",
+	">":  "$gt",
+	">=": "$gte",
+}
+
+// filterError is a syntax or type error at a 1-based character position of
+// the filter.
+type filterError struct {
+	pos int
+	msg string
+}
+
+func (e *filterError) Error() string {
+	return fmt.Sprintf("invalid filter at position %d: %s", e.pos, e.msg)
+}
+
+func filterErrorf(pos int, format string, args ...interface{}) *filterError {
+	return &filterError{pos: pos, msg: fmt.Sprintf(format, args...)}
+}
+
+type filterTokenKind int
+
+const (
+	tokenEOF filterTokenKind = iota
+	tokenText
+	tokenString
+	tokenComparator
+	tokenMinus
+	tokenLParen
+	tokenRParen
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+func isFilterTextRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.*+-", r)
+}
+
+// afterComparator reports whether the next token is the value of a
+// restriction, where a leading - is a sign rather than NOT.
+func afterComparator(tokens []filterToken) bool {
+	return len(tokens) > 0 && tokens[len(tokens)-1].kind == tokenComparator
+}
+
+func lexFilter(s string) ([]filterToken, error) {
+	runes := []rune(s)
+	var tokens []filterToken
+	for i := 0; i < len(runes); {
+		r, pos := runes[i], i+1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, filterToken{tokenLParen, "(", pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{tokenRParen, ")", pos})
+			i++
+		case r == '-' && !afterComparator(tokens):
+			tokens = append(tokens, filterToken{tokenMinus, "-", pos})
+			i++
+		case strings.ContainsRune("=!<>:", r):
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' && r != '=' && r != ':' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, filterErrorf(pos, "expected != operator")
+			}
+			tokens = append(tokens, filterToken{tokenComparator, op, pos})
+			i += len(op)
+		case r == '"' || r == '\'':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				b.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, filterErrorf(pos, "unterminated string")
+			}
+			tokens = append(tokens, filterToken{tokenString, b.String(), pos})
+			i = j + 1
+		case isFilterTextRune(r):
+			j := i
+			for j < len(runes) && isFilterTextRune(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, filterToken{tokenText, string(runes[i:j]), pos})
+			i = j
+		default:
+			return nil, filterErrorf(pos, "unexpected character %q", r)
+		}
+	}
+	return append(tokens, filterToken{tokenEOF, "", len(runes) + 1}), nil
+}
+
+// filterNode is a node of a parsed filter: filterAnd, filterOr, filterNot
+// or filterRestriction.
+type filterNode interface{}
+
+type filterAnd struct {
+	terms []filterNode
+}
+
+type filterOr struct {
+	terms []filterNode
+}
+
+type filterNot struct {
+	term filterNode
+}
+
+type filterRestriction struct {
+	field filterToken
+	op    filterToken
+	value filterToken
+}
+
+type filterParser struct {
+	tokens []filterToken
+	next   int
+	depth  int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.next]
+}
+
+func (p *filterParser) take() filterToken {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *filterParser) atKeyword(kw string) bool {
+	t := p.peek()
+	return t.kind == tokenText && t.text == kw
+}
+
+// parseFilter parses a filter into its syntax tree.
+func parseFilter(s string) (filterNode, error) {
+	if len(s) > maxFilterLength {
+		return nil, filterErrorf(maxFilterLength, "filter is longer than %d bytes", maxFilterLength)
+	}
+	tokens, err := lexFilter(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	n, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, filterErrorf(t.pos, "unexpected %q", t.text)
+	}
+	return n, nil
+}
+
+func (p *filterParser) expression() (filterNode, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxFilterDepth {
+		return nil, filterErrorf(p.peek().pos, "filter is nested deeper than %d levels", maxFilterDepth)
+	}
+
+	n, err := p.sequence()
+	if err != nil {
+		return nil, err
+	}
+	terms := []filterNode{n}
+	for p.atKeyword("AND") {
+		p.take()
+		n, err := p.sequence()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, n)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return &filterAnd{terms: terms}, nil
+}
+
+func (p *filterParser) sequence() (filterNode, error) {
+	n, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	terms := []filterNode{n}
+	for {
+		t := p.peek()
+		if t.kind == tokenEOF || t.kind == tokenRParen || p.atKeyword("AND") {
+			break
+		}
+		n, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, n)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return &filterAnd{terms: terms}, nil
+}
+
+func (p *filterParser) factor() (filterNode, error) {
+	n, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	terms := []filterNode{n}
+	for p.atKeyword("OR") {
+		p.take()
+		n, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, n)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return &filterOr{terms: terms}, nil
+}
+
+func (p *filterParser) term() (filterNode, error) {
+	if p.atKeyword("NOT") || p.peek().kind == tokenMinus {
+		p.take()
+		n, err := p.simple()
+		if err != nil {
+			return nil, err
+		}
+		return &filterNot{term: n}, nil
+	}
+	return p.simple()
+}
+
+func (p *filterParser) simple() (filterNode, error) {
+	t := p.take()
+	switch {
+	case t.kind == tokenLParen:
+		n, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if c := p.take(); c.kind != tokenRParen {
+			return nil, filterErrorf(c.pos, "expected ) to close ( at position %d", t.pos)
+		}
+		return n, nil
+	case t.kind == tokenText && t.text != "AND" && t.text != "OR" && t.text != "NOT":
+		op := p.take()
+		if op.kind != tokenComparator {
+			return nil, filterErrorf(op.pos, "expected a comparison operator after %q", t.text)
+		}
+		v := p.take()
+		if v.kind != tokenText && v.kind != tokenString {
+			return nil, filterErrorf(v.pos, "expected a value after %q", op.text)
+		}
+		return &filterRestriction{field: t, op: op, value: v}, nil
+	case t.kind == tokenEOF:
+		return nil, filterErrorf(t.pos, "unexpected end of filter")
+	}
+	return nil, filterErrorf(t.pos, "unexpected %q", t.text)
+}
+
+// filterQuery translates a parsed filter into a MongoDB query.
+func filterQuery(n filterNode) (bson.M, error) {
+	switch n := n.(type) {
+	case *filterAnd:
+		terms, err := filterQueries(n.terms)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"$and": terms}, nil
+	case *filterOr:
+		terms, err := filterQueries(n.terms)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"$or": terms}, nil
+	case *filterNot:
+		q, err := filterQuery(n.term)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"$nor": bson.A{q}}, nil
+	case *filterRestriction:
+		return restrictionQuery(n)
+	}
+	return nil, fmt.Errorf("unknown filter node %T", n)
+}
+
+func filterQueries(nodes []filterNode) (bson.A, error) {
+	res := make(bson.A, 0, len(nodes))
+	for _, n := range nodes {
+		q, err := filterQuery(n)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, q)
+	}
+	return res, nil
+}
+
+func restrictionQuery(r *filterRestriction) (bson.M, error) {
+	f, ok := filterFields[r.field.text]
+	if !ok {
+		return nil, filterErrorf(r.field.pos, "unknown field %q", r.field.text)
+	}
+	op, v := r.op.text, r.value.text
+
+	// field:* tests whether the field is set.
+	if op == ":" && v == "*" && r.value.kind == tokenText {
+		switch f.kind {
+		case filterString, filterUser:
+			return bson.M{f.key: bson.M{"$exists": true, "$ne": ""}}, nil
+		case filterLabels:
+			return bson.M{f.key + ".0": bson.M{"$exists": true}}, nil
+		case filterID, filterTime:
+			return bson.M{f.key: bson.M{"$exists": true}}, nil
+		}
+		return nil, filterErrorf(r.op.pos, "%s is always set", r.field.text)
+	}
+
+	ordered := false
+	var value interface{}
+	switch f.kind {
+	case filterString:
+		if op == ":" {
+			return bson.M{f.key: primitive.Regex{Pattern: regexp.QuoteMeta(v), Options: "i"}}, nil
+		}
+		value = v
+	case filterUser:
+		value = v
+	case filterLabels:
+		if op != ":" {
+			return nil, filterErrorf(r.op.pos, "labels only supports the : operator")
+		}
+		return bson.M{f.key: strings.TrimSpace(v)}, nil
+	case filterID:
+		oid, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			return nil, filterErrorf(r.value.pos, "%q is not a valid ID", v)
+		}
+		value = oid
+	case filterBool:
+		b, err := strconv.ParseBool(v)
+		if err != nil || r.value.kind != tokenText {
+			return nil, filterErrorf(r.value.pos, "expected true or false, got %q", v)
+		}
+		value = b
+	case filterInt:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, filterErrorf(r.value.pos, "expected an integer, got %q", v)
+		}
+		value, ordered = n, true
+	case filterTime:
+		t, err := parseFilterTime(v)
+		if err != nil {
+			return nil, filterErrorf(r.value.pos, "expected an RFC 3339 time or a YYYY-MM-DD date, got %q", v)
+		}
+		value, ordered = t, true
+	case filterStatus:
+		n, ok := enumValue(api.Status_value, "STATUS_", v)
+		if !ok {
+			return nil, filterErrorf(r.value.pos, "unknown status %q", v)
+		}
+		value = api.Status(n)
+	case filterPriority:
+		n, ok := enumValue(api.Priority_value, "PRIORITY_", v)
+		if !ok {
+			return nil, filterErrorf(r.value.pos, "unknown priority %q", v)
+		}
+		value, ordered = api.Priority(n), true
+	}
+
+	mop, ok := filterOperators[op]
+	if !ok || (!ordered && mop != "$eq" && mop != "$ne") {
+		return nil, filterErrorf(r.op.pos, "operator %s is not supported for %s", op, r.field.text)
+	}
+	return bson.M{f.key: bson.M{mop: value}}, nil
+}
+
+// enumValue looks up an enum value by its name, with or without prefix.
+func enumValue(values map[string]int32, prefix, name string) (int32, bool) {
+	name = strings.ToUpper(name)
+	if n, ok := values[name]; ok {
+		return n, true
+	}
+	n, ok := values[prefix+name]
+	return n, ok
+}
+
+func parseFilterTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02", v)
+}
+
+// listFilterQuery parses the filter of a ListTask request into a MongoDB
+// query, reporting errors as InvalidArgument.
+func listFilterQuery(s string) (bson.M, error) {
+	n, err := parseFilter(s)
+	if err == nil {
+		var q bson.M
+		if q, err = filterQuery(n); err == nil {
+			return q, nil
+		}
+	}
+	return nil, status.Errorf(
+		codes.InvalidArgument,
+		"[ERROR] %v", err,
+	)
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// formatFilter renders a syntax tree with explicit parentheses so tests can
+// check how a filter was grouped.
+func formatFilter(n filterNode) string {
+	join := func(terms []filterNode, sep string) string {
+		parts := make([]string, len(terms))
+		for i, t := range terms {
+			parts[i] = formatFilter(t)
+		}
+		return "(" + strings.Join(parts, sep) + ")"
+	}
+	switch n := n.(type) {
+	case *filterAnd:
+		return join(n.terms, " AND ")
+	case *filterOr:
+		return join(n.terms, " OR ")
+	case *filterNot:
+		return "NOT " + formatFilter(n.term)
+	case *filterRestriction:
+		v := n.value.text
+		if n.value.kind == tokenString {
+			v = fmt.Sprintf("%q", v)
+		}
+		return n.field.text + " " + n.op.text + " " + v
+	}
+	return fmt.Sprintf("%T", n)
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   string
+	}{
+		{`a = 1`, `a = 1`},
+		{`a=1`, `a = 1`},
+		{`a>=1`, `a >= 1`},
+		{`a != 1`, `a != 1`},
+		{`name:"a b"`, `name : "a b"`},
+		{`name = 'it\'s'`, `name = "it's"`},
+		{`a = 1 b = 2`, `(a = 1 AND b = 2)`},
+		{`a = 1 AND b = 2 AND c = 3`, `(a = 1 AND b = 2 AND c = 3)`},
+		// OR binds tighter than AND.
+		{`a = 1 OR b = 2 AND c = 3`, `((a = 1 OR b = 2) AND c = 3)`},
+		{`a = 1 AND b = 2 OR c = 3`, `(a = 1 AND (b = 2 OR c = 3))`},
+		{`a = 1 b = 2 OR c = 3`, `(a = 1 AND (b = 2 OR c = 3))`},
+		{`(a = 1 AND b = 2) OR c = 3`, `((a = 1 AND b = 2) OR c = 3)`},
+		{`((a = 1))`, `a = 1`},
+		// NOT applies to the next simple term only.
+		{`NOT a = 1`, `NOT a = 1`},
+		{`-a = 1`, `NOT a = 1`},
+		{`NOT a = 1 OR b = 2`, `(NOT a = 1 OR b = 2)`},
+		{`NOT (a = 1 OR b = 2)`, `NOT (a = 1 OR b = 2)`},
+		{`name:"deploy" -done = true`, `(name : "deploy" AND NOT done = true)`},
+		// A - right after a comparator is a sign.
+		{`revision > -1`, `revision > -1`},
+		{`story_points = -2`, `story_points = -2`},
+		{`-story_points = -2`, `NOT story_points = -2`},
+		{`revision>-1 -done=true`, `(revision > -1 AND NOT done = true)`},
+		{`due_time < "2026-11-01T10:00:00Z"`, `due_time < "2026-11-01T10:00:00Z"`},
+		{`labels:*`, `labels : *`},
+	}
+	for _, tt := range tests {
+		n, err := parseFilter(tt.filter)
+		if err != nil {
+			t.Errorf("parseFilter(%q) failed: %v", tt.filter, err)
+			continue
+		}
+		if got := formatFilter(n); got != tt.want {
+			t.Errorf("parseFilter(%q) = %s, want %s", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []struct {
+		filter string
+		pos    int
+		msg    string
+	}{
+		{``, 1, "unexpected end of filter"},
+		{`a =`, 4, "expected a value"},
+		{`a 1`, 3, "expected a comparison operator"},
+		{`a = (`, 5, "expected a value"},
+		{`(a = 1`, 7, "expected ) to close ( at position 1"},
+		{`a = 1)`, 6, `unexpected ")"`},
+		{`a ! 1`, 3, "expected != operator"},
+		{`name = "x`, 8, "unterminated string"},
+		{`a = 1 AND`, 10, "unexpected end of filter"},
+		{`a = 1 OR OR b = 2`, 10, `unexpected "OR"`},
+		{`NOT NOT a = 1`, 5, `unexpected "NOT"`},
+		{`a # 1`, 3, "unexpected character"},
+		{`a = 1 - `, 9, "unexpected end of filter"},
+		{strings.Repeat("(", 40) + "a = 1" + strings.Repeat(")", 40), 33, "nested deeper"},
+	}
+	for _, tt := range tests {
+		_, err := parseFilter(tt.filter)
+		var fe *filterError
+		if !errors.As(err, &fe) {
+			t.Errorf("parseFilter(%q) error = %v, want a filter error", tt.filter, err)
+			continue
+		}
+		if fe.pos != tt.pos || !strings.Contains(fe.msg, tt.msg) {
+			t.Errorf("parseFilter(%q) error = %v, want %q at position %d", tt.filter, err, tt.msg, tt.pos)
+		}
+	}
+}
+
+func TestFilterQuery(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   bson.M
+	}{
+		{`revision > -1`, bson.M{"revision": bson.M{"$gt": int64(-1)}}},
+		{`story_points = -2`, bson.M{"story_points": bson.M{"$eq": int64(-2)}}},
+		{`done = false`, bson.M{"done": bson.M{"$eq": false}}},
+		{`status = done`, bson.M{"status": bson.M{"$eq": api.Status_STATUS_DONE}}},
+		{`labels:urgent`, bson.M{"labels": "urgent"}},
+		{`assignee_id:*`, bson.M{"assignee_id": bson.M{"$exists": true, "$ne": ""}}},
+		{`NOT done = true`, bson.M{"$nor": bson.A{bson.M{"done": bson.M{"$eq": true}}}}},
+		{`done = true OR blocked = true`, bson.M{"$or": bson.A{
+			bson.M{"done": bson.M{"$eq": true}},
+			bson.M{"blocked": bson.M{"$eq": true}},
+		}}},
+	}
+	for _, tt := range tests {
+		n, err := parseFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("parseFilter(%q) failed: %v", tt.filter, err)
+		}
+		got, err := filterQuery(n)
+		if err != nil {
+			t.Errorf("filterQuery(%q) failed: %v", tt.filter, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("filterQuery(%q) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestFilterQueryErrors(t *testing.T) {
+	tests := []struct {
+		filter string
+		pos    int
+		msg    string
+	}{
+		{`foo = 1`, 1, `unknown field "foo"`},
+		{`done = maybe`, 8, "expected true or false"},
+		{`done = "true"`, 8, "expected true or false"},
+		{`labels = x`, 8, "only supports the : operator"},
+		{`name < "x"`, 6, "operator < is not supported"},
+		{`revision > -x`, 12, "expected an integer"},
+		{`due_time < tomorrow`, 12, "expected an RFC 3339 time"},
+		{`status = open`, 10, `unknown status "open"`},
+		{`id = 42`, 6, "not a valid ID"},
+		{`done:*`, 5, "always set"},
+		{`done = true OR foo = 2`, 16, `unknown field "foo"`},
+	}
+	for _, tt := range tests {
+		n, err := parseFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("parseFilter(%q) failed: %v", tt.filter, err)
+		}
+		_, err = filterQuery(n)
+		var fe *filterError
+		if !errors.As(err, &fe) {
+			t.Errorf("filterQuery(%q) error = %v, want a filter error", tt.filter, err)
+			continue
+		}
+		if fe.pos != tt.pos || !strings.Contains(fe.msg, tt.msg) {
+			t.Errorf("filterQuery(%q) error = %v, want %q at position %d", tt.filter, err, tt.msg, tt.pos)
+		}
+	}
+}
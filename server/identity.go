@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	userIDHeader    = "x-user-id"
+	requestIDHeader = "x-request-id"
+)
+
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// actorFromContext returns the ID of the calling user, taken from the
+// x-user-id request metadata.
+func actorFromContext(ctx context.Context) string {
+	return metadataValue(ctx, userIDHeader)
+}
+
+// requireActor returns the caller, failing with Unauthenticated for
+// anonymous calls.
+func requireActor(ctx context.Context) (string, error) {
+	actor := actorFromContext(ctx)
+	if actor == "" {
+		return "", status.Errorf(
+			codes.Unauthenticated,
+			"[ERROR] %s metadata is required for this call", userIDHeader,
+		)
+	}
+	return actor, nil
+}
+
+type requestIDKey struct{}
+
+// withRequestID stores the request ID of a call in ctx: the x-request-id
+// the client sent, or a new ID when it did not send one.
+func withRequestID(ctx context.Context) context.Context {
+	id := metadataValue(ctx, requestIDHeader)
+	if id == "" {
+		id = primitive.NewObjectID().Hex()
+	}
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// requestIDFromContext returns the request ID that the interceptors stored
+// for the call, which is empty outside a call.
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestIDUnaryInterceptor gives each unary call a single request ID.
+func requestIDUnaryInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(withRequestID(ctx), req)
+}
+
+// requestIDStreamInterceptor gives each streaming call a single request ID.
+func requestIDStreamInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &requestIDStream{ServerStream: ss, ctx: withRequestID(ss.Context())})
+}
+
+type requestIDStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestIDStream) Context() context.Context {
+	return s.ctx
+}
+
+func peerAddress(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
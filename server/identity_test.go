@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// testStream is a server stream that only carries a context.
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestRequestIDUnary(t *testing.T) {
+	var ids []string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		ids = append(ids, requestIDFromContext(ctx), requestIDFromContext(ctx))
+		return nil, nil
+	}
+
+	requestIDUnaryInterceptor(context.Background(), nil, nil, handler)
+	if ids[0] == "" || ids[0] != ids[1] {
+		t.Fatalf("request IDs within a call = %q, want one non-empty ID", ids)
+	}
+
+	requestIDUnaryInterceptor(context.Background(), nil, nil, handler)
+	if ids[2] == ids[0] {
+		t.Fatalf("two calls share request ID %q", ids[0])
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestIDHeader, "req-1"))
+	requestIDUnaryInterceptor(ctx, nil, nil, handler)
+	if ids[4] != "req-1" || ids[5] != "req-1" {
+		t.Fatalf("request IDs = %q, want the x-request-id of the client", ids[4:])
+	}
+}
+
+func TestRequestIDStream(t *testing.T) {
+	var ids []string
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		ids = append(ids, requestIDFromContext(ss.Context()), requestIDFromContext(ss.Context()))
+		return nil
+	}
+
+	requestIDStreamInterceptor(nil, &testStream{ctx: context.Background()}, nil, handler)
+	if ids[0] == "" || ids[0] != ids[1] {
+		t.Fatalf("request IDs within a call = %q, want one non-empty ID", ids)
+	}
+}
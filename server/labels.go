@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxLabelLen = 64
+
+// cleanLabels trims labels and drops duplicates, rejecting empty or
+// overly long ones.
+func cleanLabels(labels []string) ([]string, error) {
+	seen := make(map[string]bool, len(labels))
+	res := make([]string, 0, len(labels))
+	for _, l := range labels {
+		l = strings.TrimSpace(l)
+		if l == "" || len(l) > maxLabelLen {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] label must be 1 to %d characters long", maxLabelLen,
+			)
+		}
+		if seen[l] {
+			continue
+		}
+		seen[l] = true
+		res = append(res, l)
+	}
+	return res, nil
+}
+
+func (*server) AddTaskLabels(ctx context.Context, req *api.AddTaskLabelsRequest) (*api.AddTaskLabelsResponse, error) {
+
+	log.Println("[INFO] add task labels")
+
+	labels, err := cleanLabels(req.GetLabels())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := updateTaskLabels(ctx, req.GetId(), bson.M{
+		"$addToSet": bson.M{"labels": bson.M{"$each": labels}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.AddTaskLabelsResponse{
+		Task: getTaskGRPC(data),
+	}, nil
+}
+
+func (*server) RemoveTaskLabels(ctx context.Context, req *api.RemoveTaskLabelsRequest) (*api.RemoveTaskLabelsResponse, error) {
+
+	log.Println("[INFO] remove task labels")
+
+	labels, err := cleanLabels(req.GetLabels())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := updateTaskLabels(ctx, req.GetId(), bson.M{
+		"$pullAll": bson.M{"labels": labels},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.RemoveTaskLabelsResponse{
+		Task: getTaskGRPC(data),
+	}, nil
+}
+
+// updateTaskLabels applies update to the labels of an active task in a
+// transaction and returns the updated task.
+func updateTaskLabels(ctx context.Context, id string, update bson.M) (*task, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse ID",
+		)
+	}
+	update["$set"] = bson.M{"update_time": time.Now().UTC()}
+	update["$inc"] = bson.M{"revision": 1, "event_seq": 1}
+
+	var data *task
+	err = inTransaction(ctx, func(ctx context.Context) error {
+		filter := activeFilter(bson.M{"_id": oid})
+		before, err := findTask(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if err := checkAccess(ctx, before, api.ShareRole_SHARE_ROLE_EDITOR); err != nil {
+			return err
+		}
+
+		data = newTask()
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After).SetMaxTime(queryTimeout)
+		res := collection.FindOneAndUpdate(ctx, filter, update, opts)
+		if err := res.Decode(data); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return status.Errorf(
+					codes.NotFound,
+					fmt.Sprintf("[ERROR] cannot find task with ID: %v", err),
+				)
+			}
+			return dbError(ctx, err, "cannot update labels in MongoDB")
+		}
+
+		if err := recordTaskEvent(ctx, eventTaskUpdated, data); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, oid, before, data); err != nil {
+			return err
+		}
+		return recordRevision(ctx, data)
+	})
+	return data, err
+}
@@ -2,11 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
 	"os"
 	"os/signal"
+	"strconv"
 	"time"
 
 	"github.com/dbashirov/grpc-tasks/api"
@@ -20,18 +22,53 @@ import (
 
 	// "google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var (
-	defaultPort = "8080"
-	collection  *mongo.Collection
+	defaultPort         = "8080"
+	defaultQueryTimeout = 5 * time.Second
+	mongoClient         *mongo.Client
+	collection          *mongo.Collection
+	lockCollection      *mongo.Collection
+	queryTimeout        = defaultQueryTimeout
 )
 
 type task struct {
-	ID   primitive.ObjectID `bson:"_id,omitempty"`
-	Name string             `bson:"name"`
-	Desc string             `bsoon:"desc"`
-	Done bool               `bson:"done"`
+	ID               primitive.ObjectID   `bson:"_id,omitempty"`
+	Name             string               `bson:"name"`
+	Desc             string               `bson:"desc"`
+	Done             bool                 `bson:"done"`
+	DeleteTime       *time.Time           `bson:"delete_time,omitempty"`
+	Priority         api.Priority         `bson:"priority"`
+	DueTime          *time.Time           `bson:"due_time,omitempty"`
+	Status           api.Status           `bson:"status"`
+	Labels           []string             `bson:"labels,omitempty"`
+	ProjectID        primitive.ObjectID   `bson:"project_id,omitempty"`
+	ParentID         primitive.ObjectID   `bson:"parent_id,omitempty"`
+	BlockedBy        []primitive.ObjectID `bson:"blocked_by,omitempty"`
+	Blocked          bool                 `bson:"blocked"`
+	Recurrence       string               `bson:"recurrence,omitempty"`
+	RecurrenceStart  *time.Time           `bson:"recurrence_start,omitempty"`
+	SeriesID         primitive.ObjectID   `bson:"series_id,omitempty"`
+	Series           *seriesFields        `bson:"series,omitempty"`
+	NextOccurrenceID primitive.ObjectID   `bson:"next_occurrence_id,omitempty"`
+	Reminders        []reminder           `bson:"reminders,omitempty"`
+	OwnerID          string               `bson:"owner_id,omitempty"`
+	AssigneeID       string               `bson:"assignee_id,omitempty"`
+	ACL              []shareEntry         `bson:"acl,omitempty"`
+	CreateTime       time.Time            `bson:"create_time"`
+	UpdateTime       time.Time            `bson:"update_time"`
+	Rank             string               `bson:"rank,omitempty"`
+	CompleteTime     *time.Time           `bson:"complete_time,omitempty"`
+	TrackedTime      time.Duration        `bson:"tracked_time,omitempty"`
+	StoryPoints      int32                `bson:"story_points"`
+
+	// Revision numbers the stored versions of the task.
+	Revision int64 `bson:"revision"`
+	// EventSeq numbers the events of the task in the outbox.
+	EventSeq int64 `bson:"event_seq"`
 }
 
 func newTask() *task {
@@ -39,12 +76,276 @@ func newTask() *task {
 }
 
 func getTaskGRPC(data *task) *api.Task {
-	return &api.Task{
-		Id:   data.ID.Hex(),
-		Name: data.Name,
-		Desc: data.Desc,
-		Done: data.Done,
+	res := &api.Task{
+		Id:               data.ID.Hex(),
+		Name:             data.Name,
+		Desc:             data.Desc,
+		Done:             data.Done,
+		DeleteTime:       timeToProto(data.DeleteTime),
+		Priority:         data.Priority,
+		DueTime:          timeToProto(data.DueTime),
+		Status:           data.Status,
+		Labels:           data.Labels,
+		ProjectId:        hexOrEmpty(data.ProjectID),
+		ParentId:         hexOrEmpty(data.ParentID),
+		BlockedBy:        hexIDs(data.BlockedBy),
+		Blocked:          data.Blocked,
+		Recurrence:       data.Recurrence,
+		SeriesId:         hexOrEmpty(data.SeriesID),
+		NextOccurrenceId: hexOrEmpty(data.NextOccurrenceID),
+		Reminders:        getRemindersGRPC(data.Reminders),
+		Revision:         data.Revision,
+		OwnerId:          data.OwnerID,
+		AssigneeId:       data.AssigneeID,
+		Acl:              getACLGRPC(data.ACL),
+		CreateTime:       timeToProto(&data.CreateTime),
+		UpdateTime:       timeToProto(&data.UpdateTime),
+		Rank:             data.Rank,
+		CompleteTime:     timeToProto(data.CompleteTime),
+		StoryPoints:      data.StoryPoints,
 	}
+	if data.TrackedTime > 0 {
+		res.TrackedTime = durationpb.New(data.TrackedTime)
+	}
+	return res
+}
+
+func hexOrEmpty(oid primitive.ObjectID) string {
+	if oid.IsZero() {
+		return ""
+	}
+	return oid.Hex()
+}
+
+func timeToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func timeFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// activeFilter restricts a query to tasks that are not in the trash.
+func activeFilter(filter bson.M) bson.M {
+	filter["delete_time"] = bson.M{"$exists": false}
+	return filter
+}
+
+// withQueryTimeout bounds a single MongoDB operation by the configured
+// query timeout while still honouring the caller's cancellation.
+func withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, queryTimeout)
+}
+
+// dbError converts an error returned by MongoDB into a gRPC status, reporting
+// Canceled/DeadlineExceeded when the request context is the cause.
+func dbError(ctx context.Context, err error, msg string) error {
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) || mongo.IsTimeout(err) {
+		return status.Errorf(codes.DeadlineExceeded, "[ERROR] %s: %v", msg, err)
+	}
+	var se mongo.ServerError
+	if errors.As(err, &se) && se.HasErrorLabel("TransientTransactionError") {
+		return status.Errorf(codes.Aborted, "[ERROR] %s, try again: %v", msg, err)
+	}
+	return status.Errorf(codes.Internal, "[ERROR] %s: %v", msg, err)
+}
+
+// inTransaction runs fn in a MongoDB transaction, retrying it on transient
+// errors. fn must do all its database work through the context it is given.
+func inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	sess, err := mongoClient.StartSession()
+	if err != nil {
+		return dbError(ctx, err, "cannot start MongoDB session")
+	}
+	defer sess.EndSession(context.Background())
+
+	_, err = sess.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	if _, ok := status.FromError(err); !ok {
+		return dbError(ctx, err, "transaction failed")
+	}
+	return err
+}
+
+// takeLock bumps the lock document key in the current transaction. Two
+// transactions that take the same lock conflict, so checks that snapshot
+// isolation alone would let race run one at a time.
+func takeLock(ctx context.Context, key string) error {
+	opts := options.Update().SetUpsert(true)
+	if _, err := lockCollection.UpdateOne(ctx, bson.M{"_id": key}, bson.M{"$inc": bson.M{"n": 1}}, opts); err != nil {
+		return dbError(ctx, err, "cannot take lock")
+	}
+	return nil
+}
+
+// findTask loads a task by ID, mapping a missing document to NotFound.
+func findTask(ctx context.Context, filter interface{}) (*task, error) {
+	data := newTask()
+	res := collection.FindOne(ctx, filter, options.FindOne().SetMaxTime(queryTimeout))
+	if err := res.Decode(data); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Errorf(
+				codes.NotFound,
+				fmt.Sprintf("[ERROR] cannot find task with ID: %v", err),
+			)
+		}
+		return nil, dbError(ctx, err, "cannot read task from MongoDB")
+	}
+	return data, nil
+}
+
+// ensureIndexes creates the indexes the task queries rely on.
+func ensureIndexes(ctx context.Context) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "labels", Value: 1}}},
+		{Keys: bson.D{{Key: "project_id", Value: 1}}},
+		{Keys: bson.D{{Key: "parent_id", Value: 1}}},
+		{Keys: bson.D{{Key: "blocked_by", Value: 1}}},
+		{Keys: bson.D{{Key: "series_id", Value: 1}}},
+		{Keys: bson.D{{Key: "reminders.fire_time", Value: 1}}},
+		{Keys: bson.D{{Key: "owner_id", Value: 1}}},
+		{Keys: bson.D{{Key: "assignee_id", Value: 1}}},
+		{Keys: bson.D{{Key: "acl.user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "priority", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "done", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "due_time", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "create_time", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "update_time", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "rank", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "rank", Value: 1}}},
+		{Keys: bson.D{{Key: "complete_time", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "name", Value: "text"}, {Key: "desc", Value: "text"}},
+			Options: options.Index().SetWeights(bson.M{"name": 3, "desc": 1}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = deliveryCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "state", Value: 1}, {Key: "next_attempt_time", Value: 1}}},
+		{Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "create_time", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = outboxCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "publish_time", Value: 1}, {Key: "create_time", Value: 1}}},
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "seq", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = auditCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "time", Value: 1}}},
+		{Keys: bson.D{{Key: "actor", Value: 1}, {Key: "time", Value: 1}}},
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "time", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = revisionCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "task_id", Value: 1}, {Key: "revision", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = commentCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "create_time", Value: 1}, {Key: "_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = attachmentCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "create_time", Value: 1}}},
+		{
+			// Attachments of deleted tasks waiting for sweepAttachments.
+			Keys: bson.D{{Key: "delete_time", Value: 1}},
+			Options: options.Index().
+				SetPartialFilterExpression(bson.M{"delete_time": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = boardCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "columns.statuses", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = timeEntryCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "start_time", Value: 1}}},
+		{Keys: bson.D{{Key: "start_time", Value: 1}}},
+		{
+			// One running timer per user.
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"running": true}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = sprintCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "start_time", Value: -1}}},
+		{Keys: bson.D{{Key: "task_ids", Value: 1}}},
+	})
+	return err
+}
+
+// durationEnv reads a time.Duration from the environment variable key,
+// falling back to def when it is unset.
+func durationEnv(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatalf("[ERROR] invalid %s: %v", key, err)
+	}
+	return d
+}
+
+// int64Env reads an integer from the environment variable key, falling
+// back to def when it is unset.
+func int64Env(key string, def int64) int64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		log.Fatalf("[ERROR] invalid %s: %v", key, err)
+	}
+	return n
 }
 
 type server struct {
@@ -55,19 +356,80 @@ func (*server) CreateTask(ctx context.Context, req *api.CreateTaskRequest) (*api
 
 	log.Println("[INFO] start create task")
 
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var res *api.CreateTaskResponse
+	err := inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = createTask(ctx, req)
+		return err
+	})
+	return res, err
+}
+
+// createTask stores a new task and its created event.
+func createTask(ctx context.Context, req *api.CreateTaskRequest) (*api.CreateTaskResponse, error) {
 	t := req.GetTask()
+	if err := validateTaskFields(t); err != nil {
+		return nil, err
+	}
+	st, err := requestedStatus(api.Status_STATUS_TODO, t)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := cleanLabels(t.GetLabels())
+	if err != nil {
+		return nil, err
+	}
+	projectID, err := taskProject(ctx, t.GetProjectId())
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := taskParent(ctx, primitive.NilObjectID, t.GetParentId())
+	if err != nil {
+		return nil, err
+	}
+	rank, err := endRank(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
 	data := task{
-		Name: t.GetName(),
-		Desc: t.GetDesc(),
-		Done: t.GetDone(),
+		ID:          primitive.NewObjectID(),
+		Name:        t.GetName(),
+		Desc:        t.GetDesc(),
+		Priority:    t.GetPriority(),
+		DueTime:     timeFromProto(t.GetDueTime()),
+		Labels:      labels,
+		ProjectID:   projectID,
+		ParentID:    parentID,
+		OwnerID:     actorFromContext(ctx),
+		AssigneeID:  t.GetAssigneeId(),
+		StoryPoints: t.GetStoryPoints(),
+		Rank:        rank,
+		CreateTime:  now,
+		UpdateTime:  now,
+		Revision:    1,
+		EventSeq:    1,
+	}
+	setStatus(&data, st, now)
+	if err := setRecurrence(&data, t); err != nil {
+		return nil, err
+	}
+	if err := setReminders(&data, t.GetReminders()); err != nil {
+		return nil, err
+	}
+	if !req.GetIgnoreWipLimits() {
+		if err := checkWIPLimits(ctx, &task{}, &data); err != nil {
+			return nil, err
+		}
 	}
 
 	res, err := collection.InsertOne(ctx, data)
 	if err != nil {
-		return nil, status.Errorf(
-			codes.Internal,
-			fmt.Sprintf("[ERROR] internal error: %v", err),
-		)
+		return nil, dbError(ctx, err, "internal error")
 	}
 
 	oid, ok := res.InsertedID.(primitive.ObjectID)
@@ -80,14 +442,18 @@ func (*server) CreateTask(ctx context.Context, req *api.CreateTaskRequest) (*api
 
 	log.Println("[INFO] End create task")
 
+	data.ID = oid
+	if err := recordTaskEvent(ctx, eventTaskCreated, &data); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, data.ID, nil, &data); err != nil {
+		return nil, err
+	}
+	if err := recordRevision(ctx, &data); err != nil {
+		return nil, err
+	}
 	return &api.CreateTaskResponse{
-		// Task: getTaskData(&data),
-		Task: &api.Task{
-			Id:   oid.Hex(),
-			Name: t.GetName(),
-			Desc: t.GetDesc(),
-			Done: t.GetDone(),
-		},
+		Task: getTaskGRPC(&data),
 	}, nil
 }
 
@@ -95,6 +461,9 @@ func (*server) ReadTask(ctx context.Context, req *api.ReadTaskRequest) (*api.Rea
 
 	log.Println("[INFO] read task")
 
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
 	oid, err := primitive.ObjectIDFromHex(req.GetId())
 	if err != nil {
 		return nil, status.Errorf(
@@ -103,14 +472,12 @@ func (*server) ReadTask(ctx context.Context, req *api.ReadTaskRequest) (*api.Rea
 		)
 	}
 
-	data := newTask()
-	filter := bson.M{"_id": oid}
-	res := collection.FindOne(ctx, filter)
-	if err := res.Decode(data); err != nil {
-		return nil, status.Errorf(
-			codes.NotFound,
-			fmt.Sprintf("[ERROR] cannot find task with ID: %v", err),
-		)
+	data, err := findTask(ctx, activeFilter(bson.M{"_id": oid}))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccess(ctx, data, api.ShareRole_SHARE_ROLE_VIEWER); err != nil {
+		return nil, err
 	}
 
 	return &api.ReadTaskResponse{
@@ -122,6 +489,20 @@ func (*server) UpdateTask(ctx context.Context, req *api.UpdateTaskRequest) (*api
 
 	log.Println("[INFO] update task")
 
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var res *api.UpdateTaskResponse
+	err := inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = updateTask(ctx, req)
+		return err
+	})
+	return res, err
+}
+
+// updateTask applies an update and stores the updated event.
+func updateTask(ctx context.Context, req *api.UpdateTaskRequest) (*api.UpdateTaskResponse, error) {
 	t := req.GetTask()
 	oid, err := primitive.ObjectIDFromHex(t.GetId())
 	if err != nil {
@@ -131,26 +512,114 @@ func (*server) UpdateTask(ctx context.Context, req *api.UpdateTaskRequest) (*api
 		)
 	}
 
-	data := newTask()
-	filter := bson.M{"_id": oid}
-	res := collection.FindOne(ctx, filter)
-	if err := res.Decode(data); err != nil {
+	filter := activeFilter(bson.M{"_id": oid})
+	data, err := findTask(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccess(ctx, data, api.ShareRole_SHARE_ROLE_EDITOR); err != nil {
+		return nil, err
+	}
+	before := *data
+
+	if err := validateTaskFields(t); err != nil {
+		return nil, err
+	}
+	if err := checkAssignee(ctx, data, t.GetAssigneeId()); err != nil {
+		return nil, err
+	}
+	st, err := requestedStatus(data.Status, t)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTransition(data, st, req.GetIgnoreBlockers()); err != nil {
+		return nil, err
+	}
+	statusChanged := data.Status != st
+	if req.GetScope() != api.RecurrenceScope_RECURRENCE_SCOPE_UNSPECIFIED &&
+		req.GetScope() != api.RecurrenceScope_RECURRENCE_SCOPE_THIS &&
+		req.GetScope() != api.RecurrenceScope_RECURRENCE_SCOPE_SERIES {
 		return nil, status.Errorf(
-			codes.NotFound,
-			fmt.Sprintf("[ERROR] cannot find task with ID: %v", err),
+			codes.InvalidArgument,
+			"[ERROR] unknown recurrence scope: %v", req.GetScope(),
 		)
 	}
+	labels, err := cleanLabels(t.GetLabels())
+	if err != nil {
+		return nil, err
+	}
+	projectID, err := taskProject(ctx, t.GetProjectId())
+	if err != nil {
+		return nil, err
+	}
+	// An unchanged parent is kept even if the caller cannot change it.
+	parentID := data.ParentID
+	if t.GetParentId() != hexOrEmpty(data.ParentID) {
+		if parentID, err = taskParent(ctx, oid, t.GetParentId()); err != nil {
+			return nil, err
+		}
+	}
 
 	data.Name = t.GetName()
 	data.Desc = t.GetDesc()
-	data.Done = t.GetDone()
+	data.Priority = t.GetPriority()
+	data.DueTime = timeFromProto(t.GetDueTime())
+	data.UpdateTime = time.Now().UTC()
+	setStatus(data, st, data.UpdateTime)
+	data.Revision++
+	data.EventSeq++
+	data.Labels = labels
+	if projectID != data.ProjectID {
+		// A task moved to another project goes to the end of its list.
+		if data.Rank, err = endRank(ctx, projectID); err != nil {
+			return nil, err
+		}
+	}
+	data.ProjectID = projectID
+	data.ParentID = parentID
+	data.AssigneeID = t.GetAssigneeId()
+	data.StoryPoints = t.GetStoryPoints()
+	if err := setRecurrence(data, t); err != nil {
+		return nil, err
+	}
+	if err := setReminders(data, t.GetReminders()); err != nil {
+		return nil, err
+	}
+	seriesEdit := data.editSeries(req.GetScope())
+	if !req.GetIgnoreWipLimits() {
+		if err := checkWIPLimits(ctx, &before, data); err != nil {
+			return nil, err
+		}
+	}
 
 	_, err = collection.ReplaceOne(ctx, filter, data)
 	if err != nil {
-		return nil, status.Errorf(
-			codes.Internal,
-			fmt.Sprintf("[ERROR] cannot update object in MongoDB: %v", err),
-		)
+		return nil, dbError(ctx, err, "cannot update object in MongoDB")
+	}
+	if statusChanged {
+		if err := refreshDependents(ctx, oid); err != nil {
+			return nil, err
+		}
+	}
+	if seriesEdit {
+		if err := updateSeries(ctx, data); err != nil {
+			return nil, err
+		}
+	}
+	if statusChanged && st == api.Status_STATUS_DONE {
+		if err := spawnNextOccurrence(ctx, data, req.GetIgnoreWipLimits()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := recordTaskEvent(ctx, eventTaskUpdated, data); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, oid, &before, data); err != nil {
+		return nil, err
+	}
+	if err := recordRevision(ctx, data); err != nil {
+		return nil, err
 	}
 
 	return &api.UpdateTaskResponse{
@@ -162,6 +631,20 @@ func (*server) DeleteTask(ctx context.Context, req *api.DeleteTaskRequest) (*api
 
 	log.Println("[INFO] delete task")
 
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var res *api.DeleteTaskResponse
+	err := inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = deleteTask(ctx, req)
+		return err
+	})
+	return res, err
+}
+
+// deleteTask removes a task and stores the deleted event.
+func deleteTask(ctx context.Context, req *api.DeleteTaskRequest) (*api.DeleteTaskResponse, error) {
 	oid, err := primitive.ObjectIDFromHex(req.GetId())
 	if err != nil {
 		return nil, status.Errorf(
@@ -170,20 +653,61 @@ func (*server) DeleteTask(ctx context.Context, req *api.DeleteTaskRequest) (*api
 		)
 	}
 
-	filter := bson.M{"_id": oid}
-	res, err := collection.DeleteOne(ctx, filter)
+	filter := activeFilter(bson.M{"_id": oid})
+	if req.GetForce() {
+		filter = bson.M{"_id": oid}
+	}
+	data, err := findTask(ctx, filter)
 	if err != nil {
-		return nil, status.Errorf(
-			codes.Internal,
-			fmt.Sprintf("[ERROR] cannot delete object in MongoDB: %v", err),
-		)
+		if status.Code(err) == codes.NotFound && req.GetAllowMissing() {
+			return &api.DeleteTaskResponse{
+				Id: req.GetId(),
+			}, nil
+		}
+		return nil, err
+	}
+	if err := checkAccess(ctx, data, api.ShareRole_SHARE_ROLE_EDITOR); err != nil {
+		return nil, err
 	}
 
-	if res.DeletedCount == 0 {
-		return nil, status.Errorf(
-			codes.NotFound,
-			"[ERROR] cannot fint task in MongoDB",
-		)
+	ids, err := deleteScope(ctx, data, req)
+	if err != nil {
+		return nil, err
+	}
+	before := *data
+	data.EventSeq++
+	var after *task
+
+	if req.GetForce() {
+		deleted := make([]interface{}, len(ids))
+		for i, id := range ids {
+			deleted[i] = id
+		}
+		if _, err := dropTasks(ctx, deleted); err != nil {
+			return nil, err
+		}
+	} else {
+		now := time.Now().UTC()
+		update := bson.M{
+			"$set": bson.M{"delete_time": now},
+			"$inc": bson.M{"event_seq": 1},
+		}
+		_, err := collection.UpdateMany(ctx, activeFilter(bson.M{"_id": bson.M{"$in": ids}}), update)
+		if err != nil {
+			return nil, dbError(ctx, err, "cannot move object to trash in MongoDB")
+		}
+		if err := refreshDependents(ctx, ids...); err != nil {
+			return nil, err
+		}
+		data.DeleteTime = &now
+		after = data
+	}
+
+	if err := recordTaskEvent(ctx, eventTaskDeleted, data); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, oid, &before, after); err != nil {
+		return nil, err
 	}
 
 	return &api.DeleteTaskResponse{
@@ -191,21 +715,86 @@ func (*server) DeleteTask(ctx context.Context, req *api.DeleteTaskRequest) (*api
 	}, nil
 }
 
-func (*server) ListTask(_ *api.ListTaskRequest, stream api.TaskService_ListTaskServer) error {
+func (*server) ListTask(req *api.ListTaskRequest, stream api.TaskService_ListTaskServer) error {
 
 	log.Println("[INFO] stream list tasks")
 
-	cur, err := collection.Find(context.Background(), primitive.D{})
+	ctx := stream.Context()
+	filter, order, err := listFilter(actorFromContext(ctx), req)
+	if err != nil {
+		return err
+	}
+	opts := options.Find().SetSort(orderSort(order))
+
+	// Without paging fields every task is streamed, as before paging existed.
+	var limit int64
+	if req.GetPageSize() != 0 || req.GetPageToken() != "" {
+		if limit, err = pageSize(req.GetPageSize()); err != nil {
+			return err
+		}
+		opts.SetLimit(limit + 1)
+	}
+	if req.GetPageToken() != "" {
+		values, err := decodeListPageToken(req, order)
+		if err != nil {
+			return err
+		}
+		filter = bson.M{"$and": bson.A{filter, afterFilter(order, values)}}
+	}
+
+	var sent int64
+	var last *task
+	more := false
+	err = streamTasks(ctx, visibleFilter(ctx, filter), opts, func(data *task) error {
+		if limit > 0 && sent == limit {
+			more = true
+			return nil
+		}
+		sent++
+		last = data
+		return stream.Send(&api.ListTaskResponse{
+			Task: getTaskGRPC(data),
+		})
+	})
+	if err != nil || !more {
+		return err
+	}
+
+	token, err := encodeListPageToken(req, order, last)
 	if err != nil {
 		return status.Errorf(
 			codes.Internal,
-			fmt.Sprintf("[ERROR] unknown internal error: %v\n", err),
+			"[ERROR] cannot create page token: %v", err,
 		)
 	}
+	return stream.Send(&api.ListTaskResponse{
+		NextPageToken: token,
+	})
+}
+
+// streamTasks runs filter against the task collection and passes every
+// decoded task to send until the cursor is exhausted or ctx is done.
+func streamTasks(ctx context.Context, filter interface{}, opts *options.FindOptions, send func(*task) error) error {
+	return streamDocs(ctx, collection, filter, opts, send)
+}
+
+// streamDocs runs filter against coll and passes every decoded document to
+// send until the cursor is exhausted or ctx is done.
+func streamDocs[T any](ctx context.Context, coll *mongo.Collection, filter interface{}, opts *options.FindOptions, send func(*T) error) error {
+	findCtx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	if opts == nil {
+		opts = options.Find()
+	}
+	cur, err := coll.Find(findCtx, filter, opts.SetMaxTime(queryTimeout))
+	if err != nil {
+		return dbError(findCtx, err, "unknown internal error")
+	}
 	defer cur.Close(context.Background())
 
-	for cur.Next(context.Background()) {
-		data := newTask()
+	for cur.Next(ctx) {
+		data := new(T)
 		err := cur.Decode(data)
 		if err != nil {
 			return status.Errorf(
@@ -213,16 +802,16 @@ func (*server) ListTask(_ *api.ListTaskRequest, stream api.TaskService_ListTaskS
 				fmt.Sprintf("[ERROR] error while decoding data from MongoDB: %v\n", err),
 			)
 		}
-		stream.Send(&api.ListTaskResponse{
-			Task: getTaskGRPC(data),
-		})
+		if err := send(data); err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return err
+		}
 	}
 
 	if err := cur.Err(); err != nil {
-		return status.Errorf(
-			codes.Internal,
-			fmt.Sprintf("[ERROR] unknown internal error: %v", err),
-		)
+		return dbError(ctx, err, "unknown internal error")
 	}
 
 	return nil
@@ -242,22 +831,104 @@ func main() {
 		port = defaultPort
 	}
 
+	queryTimeout = durationEnv("QUERY_TIMEOUT", defaultQueryTimeout)
+	trashRetention = durationEnv("TRASH_RETENTION", defaultTrashRetention)
+	trashPurgeInterval = durationEnv("TRASH_PURGE_INTERVAL", defaultTrashPurgeInterval)
+	reminderInterval = durationEnv("REMINDER_INTERVAL", defaultReminderInterval)
+	reminderLease = durationEnv("REMINDER_LEASE", defaultReminderLease)
+	webhookInterval = durationEnv("WEBHOOK_INTERVAL", defaultWebhookInterval)
+	outboxInterval = durationEnv("OUTBOX_INTERVAL", defaultOutboxInterval)
+	outboxRetention = durationEnv("OUTBOX_RETENTION", defaultOutboxRetention)
+	attachmentMaxSize = int64Env("ATTACHMENT_MAX_SIZE", defaultAttachmentMaxSize)
+	rankRebalanceInterval = durationEnv("RANK_REBALANCE_INTERVAL", defaultRankRebalanceInterval)
+
 	mongoURL := os.Getenv("MONGODB_URL")
 
 	log.Println("[INFO] connect to MongoDB")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURL))
+	mongoClient, err = mongo.Connect(ctx, options.Client().ApplyURI(mongoURL))
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	collection = (*mongo.Collection)(client.Database("taskdb").Collection("task"))
+	collection = (*mongo.Collection)(mongoClient.Database("taskdb").Collection("task"))
+	lockCollection = mongoClient.Database("taskdb").Collection("lock")
+	projectCollection = mongoClient.Database("taskdb").Collection("project")
+	webhookCollection = mongoClient.Database("taskdb").Collection("webhook")
+	deliveryCollection = mongoClient.Database("taskdb").Collection("webhook_delivery")
+	outboxCollection = mongoClient.Database("taskdb").Collection("outbox")
+	auditCollection = mongoClient.Database("taskdb").Collection("audit")
+	revisionCollection = mongoClient.Database("taskdb").Collection("task_revision")
+	commentCollection = mongoClient.Database("taskdb").Collection("comment")
+	attachmentCollection = mongoClient.Database("taskdb").Collection("attachment")
+	boardCollection = mongoClient.Database("taskdb").Collection("board")
+	timeEntryCollection = mongoClient.Database("taskdb").Collection("time_entry")
+	sprintCollection = mongoClient.Database("taskdb").Collection("sprint")
+	templateCollection = mongoClient.Database("taskdb").Collection("template")
+
+	if dir := os.Getenv("ATTACHMENT_DIR"); dir != "" {
+		attachmentBlobs, err = newFileStore(dir)
+	} else {
+		attachmentBlobs, err = newGridFSStore(mongoClient.Database("taskdb"))
+	}
+	if err != nil {
+		log.Fatalf("[ERROR] cannot open attachment store: %v", err)
+	}
+
+	if err := ensureIndexes(context.Background()); err != nil {
+		log.Fatalf("[ERROR] cannot create indexes: %v", err)
+	}
+
+	if err := migrateStatus(context.Background()); err != nil {
+		log.Fatalf("[ERROR] cannot migrate task status: %v", err)
+	}
+
+	if err := migrateTimestamps(context.Background()); err != nil {
+		log.Fatalf("[ERROR] cannot migrate task timestamps: %v", err)
+	}
+
+	if err := ensureInbox(context.Background()); err != nil {
+		log.Fatalf("[ERROR] cannot create inbox project: %v", err)
+	}
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go runTrashPurger(jobsCtx, trashRetention, trashPurgeInterval)
+	go runRankRebalancer(jobsCtx, rankRebalanceInterval)
+
+	scheduler := &reminderScheduler{
+		notifier: logNotifier{},
+		interval: reminderInterval,
+		lease:    reminderLease,
+	}
+	go scheduler.run(jobsCtx)
+	go newWebhookDispatcher(newWebhookClient(), webhookInterval).run(jobsCtx)
+
+	relay := &outboxRelay{
+		publisher: webhookPublisher{},
+		interval:  outboxInterval,
+		lease:     time.Minute,
+		retention: outboxRetention,
+	}
+	go relay.run(jobsCtx)
 
 	log.Println("[INFO] task service started")
-	s := grpc.NewServer()
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(requestIDUnaryInterceptor),
+		grpc.StreamInterceptor(requestIDStreamInterceptor),
+	)
 	api.RegisterTaskServiceServer(s, &server{})
+	api.RegisterProjectServiceServer(s, &projectServer{})
+	api.RegisterWebhookServiceServer(s, &webhookServer{})
+	api.RegisterAuditServiceServer(s, &auditServer{})
+	api.RegisterCommentServiceServer(s, &commentServer{})
+	api.RegisterAttachmentServiceServer(s, &attachmentServer{})
+	api.RegisterBoardServiceServer(s, &boardServer{})
+	api.RegisterTimeTrackingServiceServer(s, &timeTrackingServer{})
+	api.RegisterSprintServiceServer(s, &sprintServer{})
+	api.RegisterTemplateServiceServer(s, &templateServer{})
 
 	lis, err := net.Listen("tcp", ":"+port)
 	if err != nil {
@@ -276,8 +947,11 @@ func main() {
 
 	<-ch
 
+	log.Println("[INFO] stopping background jobs")
+	stopJobs()
+
 	log.Println("[INFO] closing MongoDB connection")
-	if err := client.Disconnect(context.Background()); err != nil {
+	if err := mongoClient.Disconnect(context.Background()); err != nil {
 		log.Fatalf("Error on disconnection with MongoDB: %v\n", err)
 	}
 
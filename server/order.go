@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"strings"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// orderFields maps the fields ListTask can sort by to document keys. Each
+// has an index on {key, _id}, see ensureIndexes.
+var orderFields = map[string]string{
+	"id":          "_id",
+	"name":        "name",
+	"priority":    "priority",
+	"status":      "status",
+	"done":        "done",
+	"due_time":    "due_time",
+	"create_time": "create_time",
+	"update_time": "update_time",
+	"rank":        "rank",
+}
+
+type orderKey struct {
+	key  string
+	desc bool
+}
+
+// parseOrderBy parses an order_by string such as "done, update_time desc".
+func parseOrderBy(s string) ([]orderKey, error) {
+	var keys []orderKey
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		f := strings.Fields(part)
+		if len(f) == 0 || len(f) > 2 {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] invalid order_by term %q", strings.TrimSpace(part),
+			)
+		}
+		key, ok := orderFields[f[0]]
+		if !ok {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] cannot order by %q", f[0],
+			)
+		}
+		if seen[key] {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] %q appears twice in order_by", f[0],
+			)
+		}
+		seen[key] = true
+
+		k := orderKey{key: key}
+		if len(f) == 2 {
+			switch strings.ToLower(f[1]) {
+			case "asc":
+			case "desc":
+				k.desc = true
+			default:
+				return nil, status.Errorf(
+					codes.InvalidArgument,
+					"[ERROR] order direction must be asc or desc, got %q", f[1],
+				)
+			}
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// listOrder works out the sort order of a ListTask request, ending in _id
+// so that the order is total and page tokens are stable.
+func listOrder(req *api.ListTaskRequest) ([]orderKey, error) {
+	var keys []orderKey
+	switch {
+	case req.GetOrderBy() != "" && req.GetSortBy() != api.TaskSortField_TASK_SORT_FIELD_UNSPECIFIED:
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] order_by and sort_by cannot be combined",
+		)
+	case req.GetOrderBy() != "":
+		var err error
+		if keys, err = parseOrderBy(req.GetOrderBy()); err != nil {
+			return nil, err
+		}
+	case req.GetSortBy() != api.TaskSortField_TASK_SORT_FIELD_UNSPECIFIED:
+		field, ok := sortFields[req.GetSortBy()]
+		if !ok {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] unknown sort field: %v", req.GetSortBy(),
+			)
+		}
+		keys = []orderKey{{key: field, desc: req.GetDescending()}}
+	}
+
+	for _, k := range keys {
+		if k.key == "_id" {
+			return keys, nil
+		}
+	}
+	// Break ties in the direction of the first key so that a single {key,
+	// _id} index serves both directions.
+	desc := len(keys) > 0 && keys[0].desc
+	return append(keys, orderKey{key: "_id", desc: desc}), nil
+}
+
+func orderSort(keys []orderKey) bson.D {
+	sort := make(bson.D, 0, len(keys))
+	for _, k := range keys {
+		dir := 1
+		if k.desc {
+			dir = -1
+		}
+		sort = append(sort, bson.E{Key: k.key, Value: dir})
+	}
+	return sort
+}
+
+// afterFilter matches the documents that sort after values, the sort key
+// values of the last document of the previous page. A missing value sorts
+// before any other, as in MongoDB.
+func afterFilter(keys []orderKey, values []interface{}) bson.M {
+	var or bson.A
+	for i, k := range keys {
+		and := bson.A{}
+		for j := 0; j < i; j++ {
+			and = append(and, bson.M{keys[j].key: values[j]})
+		}
+		v := values[i]
+		switch {
+		case !k.desc && v == nil:
+			and = append(and, bson.M{k.key: bson.M{"$ne": nil}})
+		case !k.desc:
+			and = append(and, bson.M{k.key: bson.M{"$gt": v}})
+		case v == nil:
+			// Nothing sorts after a missing value in descending order.
+			continue
+		default:
+			and = append(and, bson.M{"$or": bson.A{
+				bson.M{k.key: bson.M{"$lt": v}},
+				bson.M{k.key: nil},
+			}})
+		}
+		or = append(or, bson.M{"$and": and})
+	}
+	if len(or) == 0 {
+		return bson.M{"_id": bson.M{"$exists": false}}
+	}
+	return bson.M{"$or": or}
+}
+
+// listPageToken is the content of a ListTask page token: the sort key
+// values of the last task sent and a hash of the request it belongs to.
+type listPageToken struct {
+	Request string        `bson:"r"`
+	Values  []interface{} `bson:"v"`
+}
+
+// listRequestHash identifies the query of a ListTask request, ignoring the
+// paging fields.
+func listRequestHash(req *api.ListTaskRequest) string {
+	q := proto.Clone(req).(*api.ListTaskRequest)
+	q.PageSize = 0
+	q.PageToken = ""
+	b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(q)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+func encodeListPageToken(req *api.ListTaskRequest, keys []orderKey, last *task) (string, error) {
+	doc, err := bson.Marshal(last)
+	if err != nil {
+		return "", err
+	}
+	t := listPageToken{Request: listRequestHash(req)}
+	for _, k := range keys {
+		v, err := bson.Raw(doc).LookupErr(k.key)
+		if err != nil {
+			t.Values = append(t.Values, nil)
+			continue
+		}
+		t.Values = append(t.Values, v)
+	}
+	b, err := bson.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeListPageToken returns the sort key values stored in a page token
+// after checking that it was issued for the same query.
+func decodeListPageToken(req *api.ListTaskRequest, keys []orderKey) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(req.GetPageToken())
+	var t listPageToken
+	if err == nil {
+		err = bson.Unmarshal(b, &t)
+	}
+	if err != nil || len(t.Values) != len(keys) {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] invalid page token",
+		)
+	}
+	if t.Request != listRequestHash(req) {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] page token belongs to a different query",
+		)
+	}
+	return t.Values, nil
+}
+
+// migrateTimestamps fills in the create and update times of documents
+// written before they existed, using the creation time in the ObjectID.
+// Done tasks without a completion time are taken to have been completed at
+// their last update.
+func migrateTimestamps(ctx context.Context) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	update := bson.A{bson.M{"$set": bson.M{
+		"create_time": bson.M{"$toDate": "$_id"},
+		"update_time": bson.M{"$ifNull": bson.A{"$update_time", bson.M{"$toDate": "$_id"}}},
+	}}}
+	res, err := collection.UpdateMany(ctx, bson.M{"create_time": bson.M{"$exists": false}}, update)
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount > 0 {
+		log.Printf("[INFO] migrated timestamps of %d tasks\n", res.ModifiedCount)
+	}
+
+	filter := bson.M{"status": api.Status_STATUS_DONE, "complete_time": bson.M{"$exists": false}}
+	update = bson.A{bson.M{"$set": bson.M{"complete_time": "$update_time"}}}
+	res, err = collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount > 0 {
+		log.Printf("[INFO] migrated completion times of %d tasks\n", res.ModifiedCount)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var (
+	defaultOutboxInterval  = time.Second
+	defaultOutboxRetention = 7 * 24 * time.Hour
+	outboxInterval         = defaultOutboxInterval
+	outboxRetention        = defaultOutboxRetention
+	outboxCollection       *mongo.Collection
+)
+
+// taskEvent is a change of a task waiting in the outbox to be published.
+type taskEvent struct {
+	ID     primitive.ObjectID `bson:"_id"`
+	TaskID primitive.ObjectID `bson:"task_id"`
+	// Seq orders the events of one task.
+	Seq         int64      `bson:"seq"`
+	Event       string     `bson:"event"`
+	Task        string     `bson:"task"`
+	CreateTime  time.Time  `bson:"create_time"`
+	PublishTime *time.Time `bson:"publish_time,omitempty"`
+	LeaseUntil  *time.Time `bson:"lease_until,omitempty"`
+}
+
+// recordTaskEvent writes an event about data to the outbox. It must run in
+// the same transaction as the change it describes, after data.EventSeq has
+// been advanced and stored with the task.
+func recordTaskEvent(ctx context.Context, event string, data *task) error {
+	b, err := protojson.Marshal(getTaskGRPC(data))
+	if err != nil {
+		return err
+	}
+	_, err = outboxCollection.InsertOne(ctx, taskEvent{
+		ID:         primitive.NewObjectID(),
+		TaskID:     data.ID,
+		Seq:        data.EventSeq,
+		Event:      event,
+		Task:       string(b),
+		CreateTime: time.Now().UTC(),
+	})
+	if err != nil {
+		return dbError(ctx, err, "cannot write event to outbox")
+	}
+	return nil
+}
+
+// eventPublisher delivers task events taken from the outbox. An event may
+// be published more than once, so publishers must tolerate duplicates.
+type eventPublisher interface {
+	Publish(ctx context.Context, e *taskEvent) error
+}
+
+// webhookPublisher turns task events into webhook deliveries.
+type webhookPublisher struct{}
+
+func (webhookPublisher) Publish(ctx context.Context, e *taskEvent) error {
+	return enqueueWebhooks(ctx, e.Event, json.RawMessage(e.Task))
+}
+
+// outboxRelay publishes outbox events at least once and, for each task, in
+// the order they were written. Events are claimed with a lease so several
+// replicas can relay side by side.
+type outboxRelay struct {
+	publisher eventPublisher
+	interval  time.Duration
+	lease     time.Duration
+	retention time.Duration
+}
+
+func (r *outboxRelay) run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		skip := map[primitive.ObjectID]bool{}
+		for {
+			claimed, err := r.publishNext(ctx, skip)
+			if err != nil {
+				log.Printf("[ERROR] cannot publish task event: %v\n", err)
+			}
+			if !claimed {
+				break
+			}
+		}
+		r.cleanup(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishNext claims and publishes the oldest unpublished event of a task
+// that is not in skip. A task whose event cannot be published yet is added
+// to skip, so that the rest of the pass moves on to other tasks. It reports
+// false when there was nothing left to claim.
+func (r *outboxRelay) publishNext(ctx context.Context, skip map[primitive.ObjectID]bool) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	now := time.Now().UTC()
+	filter := bson.M{
+		"publish_time": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"lease_until": bson.M{"$exists": false}},
+			bson.M{"lease_until": bson.M{"$lt": now}},
+		},
+	}
+	if len(skip) > 0 {
+		skipped := make(bson.A, 0, len(skip))
+		for id := range skip {
+			skipped = append(skipped, id)
+		}
+		filter["task_id"] = bson.M{"$nin": skipped}
+	}
+	update := bson.M{"$set": bson.M{"lease_until": now.Add(r.lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "create_time", Value: 1}, {Key: "seq", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	e := &taskEvent{}
+	if err := outboxCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(e); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// Keep per-task order: an earlier event of the same task that another
+	// replica is still publishing, or that failed to publish, has to go
+	// first.
+	earlier, err := outboxCollection.CountDocuments(ctx, bson.M{
+		"task_id":      e.TaskID,
+		"seq":          bson.M{"$lt": e.Seq},
+		"publish_time": bson.M{"$exists": false},
+	})
+	if err != nil {
+		return false, err
+	}
+	if earlier > 0 {
+		skip[e.TaskID] = true
+		_, err := outboxCollection.UpdateOne(ctx, bson.M{"_id": e.ID}, bson.M{"$unset": bson.M{"lease_until": ""}})
+		return true, err
+	}
+
+	if err := r.publisher.Publish(ctx, e); err != nil {
+		// The lease runs out and the event is retried.
+		skip[e.TaskID] = true
+		return true, err
+	}
+
+	_, err = outboxCollection.UpdateOne(ctx, bson.M{"_id": e.ID}, bson.M{
+		"$set":   bson.M{"publish_time": time.Now().UTC()},
+		"$unset": bson.M{"lease_until": ""},
+	})
+	return true, err
+}
+
+// cleanup drops published events older than the retention period.
+func (r *outboxRelay) cleanup(ctx context.Context) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	cutoff := time.Now().UTC().Add(-r.retention)
+	_, err := outboxCollection.DeleteMany(ctx, bson.M{"publish_time": bson.M{"$lt": cutoff}})
+	if err != nil {
+		log.Printf("[ERROR] cannot clean up outbox: %v\n", err)
+	}
+}
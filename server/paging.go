@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// pageSize validates a requested page size, applying the default when unset.
+func pageSize(n int32) (int64, error) {
+	switch {
+	case n < 0:
+		return 0, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] page size must not be negative",
+		)
+	case n == 0:
+		return defaultPageSize, nil
+	case n > maxPageSize:
+		return maxPageSize, nil
+	}
+	return int64(n), nil
+}
+
+func encodeOffsetToken(offset int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(offset, 10)))
+}
+
+// decodeOffsetToken returns the offset stored in a page token; an empty
+// token means the first page.
+func decodeOffsetToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		var offset int64
+		offset, err = strconv.ParseInt(string(b), 10, 64)
+		if err == nil && offset >= 0 {
+			return offset, nil
+		}
+	}
+	return 0, status.Errorf(
+		codes.InvalidArgument,
+		"[ERROR] invalid page token",
+	)
+}
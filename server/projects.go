@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const inboxName = "Inbox"
+
+var (
+	projectCollection *mongo.Collection
+	inboxID           primitive.ObjectID
+)
+
+type project struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty"`
+	Name    string             `bson:"name"`
+	Desc    string             `bson:"desc"`
+	Inbox   bool               `bson:"inbox,omitempty"`
+	OwnerID string             `bson:"owner_id,omitempty"`
+}
+
+func getProjectGRPC(data *project) *api.Project {
+	return &api.Project{
+		Id:      data.ID.Hex(),
+		Name:    data.Name,
+		Desc:    data.Desc,
+		Inbox:   data.Inbox,
+		OwnerId: data.OwnerID,
+	}
+}
+
+// ensureInbox loads the inbox project, creating it on first start, and moves
+// tasks that have no project into it.
+func ensureInbox(ctx context.Context) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data := &project{}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	update := bson.M{"$setOnInsert": bson.M{"name": inboxName, "desc": ""}}
+	if err := projectCollection.FindOneAndUpdate(ctx, bson.M{"inbox": true}, update, opts).Decode(data); err != nil {
+		return err
+	}
+	inboxID = data.ID
+
+	res, err := collection.UpdateMany(ctx, bson.M{"project_id": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"project_id": inboxID}})
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount > 0 {
+		log.Printf("[INFO] moved %d tasks to inbox\n", res.ModifiedCount)
+	}
+	return nil
+}
+
+// taskProject resolves the project a task should be stored in, defaulting
+// to the inbox and checking that the project exists. It takes the lock of the
+// project so that the project cannot be deleted before the task is stored.
+func taskProject(ctx context.Context, id string) (primitive.ObjectID, error) {
+	if id == "" {
+		return inboxID, nil
+	}
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse project ID",
+		)
+	}
+	if oid != inboxID {
+		if err := takeLock(ctx, projectLock(oid)); err != nil {
+			return primitive.NilObjectID, err
+		}
+	}
+	n, err := projectCollection.CountDocuments(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return primitive.NilObjectID, dbError(ctx, err, "cannot read project from MongoDB")
+	}
+	if n == 0 {
+		return primitive.NilObjectID, status.Errorf(
+			codes.FailedPrecondition,
+			"[ERROR] project %s does not exist", id,
+		)
+	}
+	return oid, nil
+}
+
+// projectLock is the lock taken by the tasks stored in project id and by
+// its deletion.
+func projectLock(id primitive.ObjectID) string {
+	return "project:" + id.Hex()
+}
+
+func findProject(ctx context.Context, oid primitive.ObjectID) (*project, error) {
+	data := &project{}
+	res := projectCollection.FindOne(ctx, bson.M{"_id": oid}, options.FindOne().SetMaxTime(queryTimeout))
+	if err := res.Decode(data); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Errorf(
+				codes.NotFound,
+				fmt.Sprintf("[ERROR] cannot find project with ID: %v", err),
+			)
+		}
+		return nil, dbError(ctx, err, "cannot read project from MongoDB")
+	}
+	return data, nil
+}
+
+func validateProject(p *api.Project) error {
+	if strings.TrimSpace(p.GetName()) == "" {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] project name must not be empty",
+		)
+	}
+	return nil
+}
+
+type projectServer struct {
+	api.ProjectServiceServer
+}
+
+func (*projectServer) CreateProject(ctx context.Context, req *api.CreateProjectRequest) (*api.CreateProjectResponse, error) {
+
+	log.Println("[INFO] create project")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	p := req.GetProject()
+	if err := validateProject(p); err != nil {
+		return nil, err
+	}
+
+	data := project{
+		Name:    p.GetName(),
+		Desc:    p.GetDesc(),
+		OwnerID: actorFromContext(ctx),
+	}
+	res, err := projectCollection.InsertOne(ctx, data)
+	if err != nil {
+		return nil, dbError(ctx, err, "cannot create project in MongoDB")
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, status.Errorf(
+			codes.Internal,
+			"[ERROR] Cannot convert to OID",
+		)
+	}
+
+	data.ID = oid
+	return &api.CreateProjectResponse{
+		Project: getProjectGRPC(&data),
+	}, nil
+}
+
+func (*projectServer) GetProject(ctx context.Context, req *api.GetProjectRequest) (*api.GetProjectResponse, error) {
+
+	log.Println("[INFO] get project")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse ID",
+		)
+	}
+
+	data, err := findProject(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.GetProjectResponse{
+		Project: getProjectGRPC(data),
+	}, nil
+}
+
+func (*projectServer) UpdateProject(ctx context.Context, req *api.UpdateProjectRequest) (*api.UpdateProjectResponse, error) {
+
+	log.Println("[INFO] update project")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	p := req.GetProject()
+	oid, err := primitive.ObjectIDFromHex(p.GetId())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse ID",
+		)
+	}
+	if err := validateProject(p); err != nil {
+		return nil, err
+	}
+
+	data, err := findProject(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOwner(ctx, data.OwnerID, "project", data.ID); err != nil {
+		return nil, err
+	}
+
+	data.Name = p.GetName()
+	data.Desc = p.GetDesc()
+
+	_, err = projectCollection.ReplaceOne(ctx, bson.M{"_id": oid}, data)
+	if err != nil {
+		return nil, dbError(ctx, err, "cannot update project in MongoDB")
+	}
+
+	return &api.UpdateProjectResponse{
+		Project: getProjectGRPC(data),
+	}, nil
+}
+
+func (*projectServer) DeleteProject(ctx context.Context, req *api.DeleteProjectRequest) (*api.DeleteProjectResponse, error) {
+
+	log.Println("[INFO] delete project")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var res *api.DeleteProjectResponse
+	err := inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = deleteProject(ctx, req)
+		return err
+	})
+	return res, err
+}
+
+// deleteProject removes a project, moving its tasks to the inbox.
+func deleteProject(ctx context.Context, req *api.DeleteProjectRequest) (*api.DeleteProjectResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse ID",
+		)
+	}
+	if oid == inboxID {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			"[ERROR] the inbox project cannot be deleted",
+		)
+	}
+	if err := takeLock(ctx, projectLock(oid)); err != nil {
+		return nil, err
+	}
+	data, err := findProject(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOwner(ctx, data.OwnerID, "project", data.ID); err != nil {
+		return nil, err
+	}
+
+	if req.GetMoveTasksToInbox() {
+		if err := moveProjectTasks(ctx, oid, inboxID); err != nil {
+			return nil, err
+		}
+	} else {
+		n, err := collection.CountDocuments(ctx, activeFilter(bson.M{"project_id": oid}))
+		if err != nil {
+			return nil, dbError(ctx, err, "cannot count project tasks")
+		}
+		if n > 0 {
+			return nil, status.Errorf(
+				codes.FailedPrecondition,
+				"[ERROR] project still has %d tasks", n,
+			)
+		}
+		// Trashed tasks must not keep pointing at a missing project.
+		if err := moveProjectTasks(ctx, oid, inboxID); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := projectCollection.DeleteOne(ctx, bson.M{"_id": oid}); err != nil {
+		return nil, dbError(ctx, err, "cannot delete project in MongoDB")
+	}
+	if err := dropBoards(ctx, oid); err != nil {
+		return nil, err
+	}
+
+	return &api.DeleteProjectResponse{
+		Id: req.GetId(),
+	}, nil
+}
+
+func (*projectServer) ListProjects(_ *api.ListProjectsRequest, stream api.ProjectService_ListProjectsServer) error {
+
+	log.Println("[INFO] stream list projects")
+
+	ctx := stream.Context()
+
+	findCtx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "inbox", Value: -1}, {Key: "name", Value: 1}}).SetMaxTime(queryTimeout)
+	cur, err := projectCollection.Find(findCtx, bson.M{}, opts)
+	if err != nil {
+		return dbError(findCtx, err, "cannot list projects")
+	}
+	defer cur.Close(context.Background())
+
+	for cur.Next(ctx) {
+		data := &project{}
+		if err := cur.Decode(data); err != nil {
+			return status.Errorf(
+				codes.Internal,
+				"[ERROR] error while decoding data from MongoDB: %v", err,
+			)
+		}
+		err := stream.Send(&api.ListProjectsResponse{
+			Project: getProjectGRPC(data),
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return err
+		}
+	}
+
+	if err := cur.Err(); err != nil {
+		return dbError(ctx, err, "cannot list projects")
+	}
+
+	return nil
+}
@@ -0,0 +1,452 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rankDigits are the digits of ranks in increasing order, so that ranks
+// compare as plain strings.
+const rankDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// maxRankLen is the rank length above which the ranks of a project are
+// spread out again by the rebalancer.
+const maxRankLen = 24
+
+var (
+	defaultRankRebalanceInterval = time.Hour
+	rankRebalanceInterval        = defaultRankRebalanceInterval
+)
+
+// midRank returns a rank that sorts strictly between a and b. An empty a
+// means the start of the list and an empty b its end. Neither may end in
+// the zero digit, and neither does the result.
+func midRank(a, b string) string {
+	if b != "" {
+		// Keep the common prefix, reading a missing digit of a as zero.
+		n := 0
+		for n < len(b) {
+			d := rankDigits[0]
+			if n < len(a) {
+				d = a[n]
+			}
+			if d != b[n] {
+				break
+			}
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midRank(rest, b[n:])
+		}
+	}
+
+	da, db := 0, len(rankDigits)
+	if a != "" {
+		da = strings.IndexByte(rankDigits, a[0])
+	}
+	if b != "" {
+		db = strings.IndexByte(rankDigits, b[0])
+	}
+	if db-da > 1 {
+		return rankDigits[(da+db)/2 : (da+db)/2+1]
+	}
+	// The first digits are adjacent.
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return rankDigits[da:da+1] + midRank(rest, "")
+}
+
+// spreadRanks returns n increasing ranks of equal length, evenly spaced so
+// that there is room between them.
+func spreadRanks(n int) []string {
+	base := len(rankDigits)
+	width, space := 1, base
+	for space <= n {
+		width++
+		space *= base
+	}
+	step := space / (n + 1)
+
+	res := make([]string, n)
+	for i := range res {
+		v := (i + 1) * step
+		b := make([]byte, width)
+		for j := width - 1; j >= 0; j-- {
+			b[j] = rankDigits[v%base]
+			v /= base
+		}
+		// The middle digit as suffix keeps ranks from ending in zero.
+		res[i] = string(b) + rankDigits[base/2:base/2+1]
+	}
+	return res
+}
+
+// endRank returns a rank after every task of a project. Tasks created at
+// the same time can get the same rank; every query sorted by rank breaks
+// the tie by _id, and moving either task gives it a rank of its own.
+func endRank(ctx context.Context, projectID primitive.ObjectID) (string, error) {
+	data := newTask()
+	opts := options.FindOne().SetSort(bson.D{{Key: "rank", Value: -1}})
+	err := collection.FindOne(ctx, bson.M{"project_id": projectID, "rank": bson.M{"$exists": true}}, opts).Decode(data)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return "", dbError(ctx, err, "cannot read task rank")
+	}
+	return midRank(data.Rank, ""), nil
+}
+
+// moveProjectTasks moves every task of project from to the end of project
+// to, keeping their order.
+func moveProjectTasks(ctx context.Context, from, to primitive.ObjectID) error {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "rank", Value: 1}, {Key: "_id", Value: 1}}).
+		SetProjection(bson.M{"_id": 1})
+	cur, err := collection.Find(ctx, bson.M{"project_id": from}, opts)
+	if err != nil {
+		return dbError(ctx, err, "cannot read project tasks")
+	}
+	var ids []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cur.All(ctx, &ids); err != nil {
+		return dbError(ctx, err, "cannot read project tasks")
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// Every rank that starts with the end rank sorts after the tasks
+	// already in the project.
+	end, err := endRank(ctx, to)
+	if err != nil {
+		return err
+	}
+	ranks := spreadRanks(len(ids))
+	models := make([]mongo.WriteModel, len(ids))
+	for i, id := range ids {
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id.ID}).
+			SetUpdate(bson.M{"$set": bson.M{"project_id": to, "rank": end + ranks[i]}})
+	}
+	if _, err := collection.BulkWrite(ctx, models); err != nil {
+		return dbError(ctx, err, "cannot move project tasks")
+	}
+	return nil
+}
+
+// neighbourRank returns the rank of the task next to rank in a project,
+// skipping the task being moved, or "" at the ends of the list.
+func neighbourRank(ctx context.Context, data *task, rank string, next bool) (string, error) {
+	op, dir := "$lt", -1
+	if next {
+		op, dir = "$gt", 1
+	}
+	filter := bson.M{
+		"project_id": data.ProjectID,
+		"_id":        bson.M{"$ne": data.ID},
+		"rank":       bson.M{op: rank},
+	}
+	n := newTask()
+	opts := options.FindOne().SetSort(bson.D{{Key: "rank", Value: dir}})
+	if err := collection.FindOne(ctx, activeFilter(filter), opts).Decode(n); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return "", nil
+		}
+		return "", dbError(ctx, err, "cannot read task rank")
+	}
+	return n.Rank, nil
+}
+
+// rebalanceRanks gives the tasks of a project evenly spaced ranks in their
+// current order. Tasks without a rank go first, oldest first.
+func rebalanceRanks(ctx context.Context, projectID primitive.ObjectID) error {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "rank", Value: 1}, {Key: "_id", Value: 1}}).
+		SetProjection(bson.M{"_id": 1})
+	cur, err := collection.Find(ctx, bson.M{"project_id": projectID}, opts)
+	if err != nil {
+		return dbError(ctx, err, "cannot read task ranks")
+	}
+	var ids []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cur.All(ctx, &ids); err != nil {
+		return dbError(ctx, err, "cannot read task ranks")
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	ranks := spreadRanks(len(ids))
+	models := make([]mongo.WriteModel, len(ids))
+	for i, id := range ids {
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id.ID}).
+			SetUpdate(bson.M{"$set": bson.M{"rank": ranks[i]}})
+	}
+	if _, err := collection.BulkWrite(ctx, models); err != nil {
+		return dbError(ctx, err, "cannot update task ranks")
+	}
+	return nil
+}
+
+// runRankRebalancer rebalances the projects that have tasks without a rank
+// or with ranks longer than maxRankLen, checking every interval until ctx
+// is cancelled.
+func runRankRebalancer(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		rebalanceLongRanks(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func rebalanceLongRanks(ctx context.Context) {
+	findCtx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"rank": bson.M{"$exists": false}},
+		bson.M{"$expr": bson.M{"$gt": bson.A{bson.M{"$strLenBytes": "$rank"}, maxRankLen}}},
+	}}
+	projects, err := collection.Distinct(findCtx, "project_id", filter)
+	if err != nil {
+		log.Printf("[ERROR] cannot rebalance task ranks: %v\n", err)
+		return
+	}
+
+	for _, p := range projects {
+		oid, ok := p.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		err := inTransaction(ctx, func(ctx context.Context) error {
+			return rebalanceRanks(ctx, oid)
+		})
+		if err != nil {
+			log.Printf("[ERROR] cannot rebalance task ranks of project %s: %v\n", oid.Hex(), err)
+			continue
+		}
+		log.Printf("[INFO] rebalanced task ranks of project %s\n", oid.Hex())
+	}
+}
+
+func (*server) MoveTask(ctx context.Context, req *api.MoveTaskRequest) (*api.MoveTaskResponse, error) {
+
+	log.Println("[INFO] move task")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var res *api.MoveTaskResponse
+	err := inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = moveTask(ctx, req)
+		return err
+	})
+	return res, err
+}
+
+// moveTask gives a task a rank between its requested neighbours and, to
+// move it to another board column, a new status.
+func moveTask(ctx context.Context, req *api.MoveTaskRequest) (*api.MoveTaskResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse ID",
+		)
+	}
+	reorder := req.GetBeforeId() != "" || req.GetAfterId() != ""
+	if !reorder && req.GetStatus() == api.Status_STATUS_UNSPECIFIED {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] before_id, after_id or status is required",
+		)
+	}
+	if req.GetStatus() != api.Status_STATUS_UNSPECIFIED && !validStatus(req.GetStatus()) {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] unknown status: %v", req.GetStatus(),
+		)
+	}
+	if req.GetBeforeId() == req.GetId() || req.GetAfterId() == req.GetId() {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] a task cannot be placed next to itself",
+		)
+	}
+
+	filter := activeFilter(bson.M{"_id": oid})
+	data, err := findTask(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccess(ctx, data, api.ShareRole_SHARE_ROLE_EDITOR); err != nil {
+		return nil, err
+	}
+	before := *data
+
+	st := data.Status
+	if req.GetStatus() != api.Status_STATUS_UNSPECIFIED {
+		st = req.GetStatus()
+	}
+	if err := checkTransition(data, st, false); err != nil {
+		return nil, err
+	}
+	statusChanged := data.Status != st
+
+	if reorder {
+		lo, hi, err := moveBounds(ctx, data, req)
+		if err != nil {
+			return nil, err
+		}
+		if (lo == "" && req.GetAfterId() != "") || (hi == "" && req.GetBeforeId() != "") || (hi != "" && lo >= hi) {
+			// Neighbours without a rank or with equal ranks leave no room;
+			// spread the project out and look again.
+			if err := rebalanceRanks(ctx, data.ProjectID); err != nil {
+				return nil, err
+			}
+			if lo, hi, err = moveBounds(ctx, data, req); err != nil {
+				return nil, err
+			}
+		}
+		if hi != "" && lo >= hi {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] after_id must come before before_id",
+			)
+		}
+		data.Rank = midRank(lo, hi)
+	}
+
+	data.UpdateTime = time.Now().UTC()
+	setStatus(data, st, data.UpdateTime)
+	if !req.GetIgnoreWipLimits() {
+		if err := checkWIPLimits(ctx, &before, data); err != nil {
+			return nil, err
+		}
+	}
+	data.EventSeq++
+	set := bson.M{
+		"update_time": data.UpdateTime,
+		"event_seq":   data.EventSeq,
+	}
+	update := bson.M{"$set": set}
+	if reorder {
+		set["rank"] = data.Rank
+	}
+	if statusChanged {
+		// A new status is a new revision; a new place in the list is not.
+		data.Revision++
+		set["status"] = data.Status
+		set["done"] = data.Done
+		set["revision"] = data.Revision
+		if data.CompleteTime != nil {
+			set["complete_time"] = data.CompleteTime
+		} else {
+			update["$unset"] = bson.M{"complete_time": ""}
+		}
+	}
+	if _, err := collection.UpdateOne(ctx, filter, update); err != nil {
+		return nil, dbError(ctx, err, "cannot update object in MongoDB")
+	}
+	if statusChanged {
+		if err := refreshDependents(ctx, oid); err != nil {
+			return nil, err
+		}
+		if err := recordRevision(ctx, data); err != nil {
+			return nil, err
+		}
+	}
+	if statusChanged && st == api.Status_STATUS_DONE {
+		if err := spawnNextOccurrence(ctx, data, req.GetIgnoreWipLimits()); err != nil {
+			return nil, err
+		}
+	}
+	if err := recordTaskEvent(ctx, eventTaskUpdated, data); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, oid, &before, data); err != nil {
+		return nil, err
+	}
+
+	return &api.MoveTaskResponse{
+		Task: getTaskGRPC(data),
+	}, nil
+}
+
+// moveBounds returns the ranks the moved task must sort between.
+func moveBounds(ctx context.Context, data *task, req *api.MoveTaskRequest) (lo, hi string, err error) {
+	if req.GetAfterId() != "" {
+		after, err := rankNeighbour(ctx, data, req.GetAfterId())
+		if err != nil {
+			return "", "", err
+		}
+		lo = after.Rank
+	}
+	if req.GetBeforeId() != "" {
+		next, err := rankNeighbour(ctx, data, req.GetBeforeId())
+		if err != nil {
+			return "", "", err
+		}
+		hi = next.Rank
+	}
+
+	switch {
+	case req.GetBeforeId() == "" && lo != "":
+		hi, err = neighbourRank(ctx, data, lo, true)
+	case req.GetAfterId() == "" && hi != "":
+		lo, err = neighbourRank(ctx, data, hi, false)
+	}
+	return lo, hi, err
+}
+
+// rankNeighbour loads a task the moved task is placed next to, which must
+// be in the same project.
+func rankNeighbour(ctx context.Context, data *task, id string) (*task, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse neighbour ID",
+		)
+	}
+	n, err := findTask(ctx, activeFilter(bson.M{"_id": oid}))
+	if err != nil {
+		return nil, err
+	}
+	if n.ProjectID != data.ProjectID {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			"[ERROR] task %s is in another project", id,
+		)
+	}
+	return n, nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func checkRank(t *testing.T, r string) {
+	t.Helper()
+	if r == "" {
+		t.Fatalf("rank is empty")
+	}
+	if strings.HasSuffix(r, rankDigits[:1]) {
+		t.Fatalf("rank %q ends in the zero digit", r)
+	}
+	for i := 0; i < len(r); i++ {
+		if strings.IndexByte(rankDigits, r[i]) < 0 {
+			t.Fatalf("rank %q has digit %q outside rankDigits", r, r[i])
+		}
+	}
+}
+
+func TestMidRank(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"", ""},
+		{"", "1"},
+		{"", "V"},
+		{"V", ""},
+		{"z", ""},
+		{"zzz", ""},
+		{"1", "2"},
+		{"1", "11"},
+		{"A", "B"},
+		{"A", "A1"},
+		{"A01", "A1"},
+		{"Ay", "Az"},
+		{"Azz", "B"},
+		{"V", "W"},
+		{"0001", "0002"},
+	}
+	for _, tt := range tests {
+		got := midRank(tt.a, tt.b)
+		checkRank(t, got)
+		if got <= tt.a {
+			t.Errorf("midRank(%q, %q) = %q, want after %q", tt.a, tt.b, got, tt.a)
+		}
+		if tt.b != "" && got >= tt.b {
+			t.Errorf("midRank(%q, %q) = %q, want before %q", tt.a, tt.b, got, tt.b)
+		}
+	}
+}
+
+// TestMidRankInserts inserts ranks at random places of a list and checks
+// that the list stays strictly ordered.
+func TestMidRankInserts(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var list []string
+	for i := 0; i < 2000; i++ {
+		at := rng.Intn(len(list) + 1)
+		var a, b string
+		if at > 0 {
+			a = list[at-1]
+		}
+		if at < len(list) {
+			b = list[at]
+		}
+		r := midRank(a, b)
+		checkRank(t, r)
+		list = append(list, "")
+		copy(list[at+1:], list[at:])
+		list[at] = r
+	}
+	for i := 1; i < len(list); i++ {
+		if list[i-1] >= list[i] {
+			t.Fatalf("ranks %q and %q are out of order", list[i-1], list[i])
+		}
+	}
+}
+
+// TestMidRankRepeated keeps inserting at the same place, which makes ranks
+// grow, and checks the ranks stay in order.
+func TestMidRankRepeated(t *testing.T) {
+	for _, front := range []bool{true, false} {
+		a, b := "A", "B"
+		for i := 0; i < 200; i++ {
+			r := midRank(a, b)
+			checkRank(t, r)
+			if r <= a || r >= b {
+				t.Fatalf("midRank(%q, %q) = %q, want between them", a, b, r)
+			}
+			if front {
+				b = r
+			} else {
+				a = r
+			}
+		}
+	}
+}
+
+func TestSpreadRanks(t *testing.T) {
+	for _, n := range []int{1, 2, 10, len(rankDigits) - 1, len(rankDigits), 1000, 5000} {
+		ranks := spreadRanks(n)
+		if len(ranks) != n {
+			t.Fatalf("spreadRanks(%d) returned %d ranks", n, len(ranks))
+		}
+		for _, r := range ranks {
+			checkRank(t, r)
+			if len(r) != len(ranks[0]) {
+				t.Fatalf("spreadRanks(%d) has ranks of different lengths", n)
+			}
+		}
+		if !sort.StringsAreSorted(ranks) {
+			t.Fatalf("spreadRanks(%d) is not sorted", n)
+		}
+		for i := 1; i < n; i++ {
+			if ranks[i-1] == ranks[i] {
+				t.Fatalf("spreadRanks(%d) repeats rank %q", n, ranks[i])
+			}
+		}
+		// There must be room before, between and after the ranks.
+		checkRank(t, midRank("", ranks[0]))
+		for i := 1; i < n; i++ {
+			if r := midRank(ranks[i-1], ranks[i]); r <= ranks[i-1] || r >= ranks[i] {
+				t.Fatalf("no rank between %q and %q", ranks[i-1], ranks[i])
+			}
+		}
+	}
+}
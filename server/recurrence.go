@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"github.com/teambition/rrule-go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// parseRecurrence parses an RFC 5545 RRULE, with or without the "RRULE:"
+// prefix.
+func parseRecurrence(rule string) (*rrule.ROption, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	opt, err := rrule.StrToROption(rule)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] invalid recurrence rule: %v", err,
+		)
+	}
+	return opt, nil
+}
+
+// nextOccurrence returns the first occurrence of a series starting at start
+// that falls after the given time. It reports false once the series is over.
+func nextOccurrence(rule string, start, after time.Time) (time.Time, bool, error) {
+	opt, err := parseRecurrence(rule)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	opt.Dtstart = start
+	r, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return time.Time{}, false, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] invalid recurrence rule: %v", err,
+		)
+	}
+	next := r.After(after, false)
+	return next, !next.IsZero(), nil
+}
+
+// seriesFields are the fields the occurrences of a series share. Edits with
+// RECURRENCE_SCOPE_THIS leave them alone, so the next occurrence is built
+// from them rather than from the occurrence that was edited.
+type seriesFields struct {
+	Name        string       `bson:"name"`
+	Desc        string       `bson:"desc"`
+	Priority    api.Priority `bson:"priority"`
+	Labels      []string     `bson:"labels,omitempty"`
+	Recurrence  string       `bson:"recurrence"`
+	StoryPoints int32        `bson:"story_points"`
+}
+
+// ownSeriesFields returns the fields of data that its series shares.
+func (data *task) ownSeriesFields() *seriesFields {
+	return &seriesFields{
+		Name:        data.Name,
+		Desc:        data.Desc,
+		Priority:    data.Priority,
+		Labels:      data.Labels,
+		Recurrence:  data.Recurrence,
+		StoryPoints: data.StoryPoints,
+	}
+}
+
+// sharedFields returns the series fields of data. Occurrences stored before
+// the series fields were kept fall back to their own fields.
+func (data *task) sharedFields() *seriesFields {
+	if data.Series != nil {
+		return data.Series
+	}
+	return data.ownSeriesFields()
+}
+
+// editSeries makes the fields of data the shared fields of its series when
+// an edit is scoped to the whole series, and reports whether it is.
+func (data *task) editSeries(scope api.RecurrenceScope) bool {
+	if scope != api.RecurrenceScope_RECURRENCE_SCOPE_SERIES || data.SeriesID.IsZero() {
+		return false
+	}
+	data.Series = data.ownSeriesFields()
+	return true
+}
+
+// applySeries copies the shared fields of a series to one of its
+// occurrences.
+func (data *task) applySeries(shared *seriesFields) {
+	data.Name = shared.Name
+	data.Desc = shared.Desc
+	data.Priority = shared.Priority
+	data.Labels = shared.Labels
+	data.Recurrence = shared.Recurrence
+	data.StoryPoints = shared.StoryPoints
+	data.Series = shared
+}
+
+// setRecurrence applies the recurrence rule of t to data. A task that
+// becomes recurring starts a series anchored at its due time.
+func setRecurrence(data *task, t *api.Task) error {
+	rule := strings.TrimSpace(t.GetRecurrence())
+	if rule == "" {
+		data.Recurrence = ""
+		data.Series = nil
+		return nil
+	}
+	if _, err := parseRecurrence(rule); err != nil {
+		return err
+	}
+	if data.Recurrence == "" || data.RecurrenceStart == nil {
+		start := time.Now().UTC()
+		if data.DueTime != nil {
+			start = *data.DueTime
+		}
+		data.RecurrenceStart = &start
+	}
+	starting := data.Recurrence == ""
+	if data.SeriesID.IsZero() {
+		data.SeriesID = data.ID
+	}
+	data.Recurrence = rule
+	if starting {
+		data.Series = data.ownSeriesFields()
+	}
+	return nil
+}
+
+// spawnNextOccurrence creates the occurrence that follows a recurring task
+// which has just been marked done and links it from data. Unless ignoreWIP
+// is set, the occurrence must fit in the work-in-progress limits.
+func spawnNextOccurrence(ctx context.Context, data *task, ignoreWIP bool) error {
+	if data.Recurrence == "" || !data.NextOccurrenceID.IsZero() {
+		return nil
+	}
+
+	after := time.Now().UTC()
+	if data.DueTime != nil {
+		after = *data.DueTime
+	}
+	start := after
+	if data.RecurrenceStart != nil {
+		start = *data.RecurrenceStart
+	}
+	due, ok, err := nextOccurrence(data.sharedFields().Recurrence, start, after)
+	if err != nil || !ok {
+		return err
+	}
+
+	rank, err := endRank(ctx, data.ProjectID)
+	if err != nil {
+		return err
+	}
+	next := data.occurrenceAfter(due)
+	next.Rank = rank
+	if !ignoreWIP {
+		if err := checkWIPLimits(ctx, &task{}, &next); err != nil {
+			return err
+		}
+	}
+	if _, err := collection.InsertOne(ctx, next); err != nil {
+		return dbError(ctx, err, "cannot create next occurrence")
+	}
+	if err := recordTaskEvent(ctx, eventTaskCreated, &next); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, next.ID, nil, &next); err != nil {
+		return err
+	}
+	if err := recordRevision(ctx, &next); err != nil {
+		return err
+	}
+
+	data.NextOccurrenceID = next.ID
+	_, err = collection.UpdateOne(ctx, bson.M{"_id": data.ID}, bson.M{"$set": bson.M{"next_occurrence_id": data.NextOccurrenceID}})
+	if err != nil {
+		return dbError(ctx, err, "cannot link next occurrence")
+	}
+	return nil
+}
+
+// occurrenceAfter builds the occurrence of the series of data that is due
+// at the given time.
+func (data *task) occurrenceAfter(due time.Time) task {
+	shared := data.sharedFields()
+	now := time.Now().UTC()
+	next := task{
+		ID:              primitive.NewObjectID(),
+		DueTime:         &due,
+		Status:          api.Status_STATUS_TODO,
+		ProjectID:       data.ProjectID,
+		ParentID:        data.ParentID,
+		RecurrenceStart: data.RecurrenceStart,
+		SeriesID:        data.SeriesID,
+		Reminders:       recurringReminders(data, &due),
+		OwnerID:         data.OwnerID,
+		AssigneeID:      data.AssigneeID,
+		ACL:             data.ACL,
+		CreateTime:      now,
+		UpdateTime:      now,
+		Revision:        1,
+		EventSeq:        1,
+	}
+	next.applySeries(shared)
+	return next
+}
+
+// updateSeries copies the shared fields of data to the other open
+// occurrences of its series, recording each change like an update.
+func updateSeries(ctx context.Context, data *task) error {
+	if data.SeriesID.IsZero() {
+		return nil
+	}
+	cur, err := collection.Find(ctx, activeFilter(bson.M{
+		"series_id": data.SeriesID,
+		"_id":       bson.M{"$ne": data.ID},
+		"status":    bson.M{"$nin": closedStatuses},
+	}))
+	if err != nil {
+		return dbError(ctx, err, "cannot read recurring series")
+	}
+	var list []task
+	if err := cur.All(ctx, &list); err != nil {
+		return dbError(ctx, err, "cannot read recurring series")
+	}
+
+	shared := data.sharedFields()
+	for i := range list {
+		occ := &list[i]
+		before := *occ
+		occ.applySeries(shared)
+		occ.UpdateTime = data.UpdateTime
+		occ.Revision++
+		occ.EventSeq++
+
+		if _, err := collection.ReplaceOne(ctx, bson.M{"_id": occ.ID}, occ); err != nil {
+			return dbError(ctx, err, "cannot update recurring series")
+		}
+		if err := recordTaskEvent(ctx, eventTaskUpdated, occ); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, occ.ID, &before, occ); err != nil {
+			return err
+		}
+		if err := recordRevision(ctx, occ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
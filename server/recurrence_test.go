@@ -0,0 +1,93 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// testSeries starts a weekly series and returns its first occurrence and a
+// sibling occurrence of the same series.
+func testSeries(t *testing.T) (*task, *task) {
+	t.Helper()
+	due := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	first := &task{
+		ID:          primitive.NewObjectID(),
+		Name:        "Weekly report",
+		Desc:        "Send the report",
+		Priority:    api.Priority_PRIORITY_MEDIUM,
+		DueTime:     &due,
+		Labels:      []string{"reports"},
+		StoryPoints: 1,
+	}
+	err := setRecurrence(first, &api.Task{Name: first.Name, Recurrence: "FREQ=WEEKLY"})
+	if err != nil {
+		t.Fatalf("setRecurrence failed: %v", err)
+	}
+	sibling := first.occurrenceAfter(due.AddDate(0, 0, 7))
+	return first, &sibling
+}
+
+func TestEditSeries(t *testing.T) {
+	data, sibling := testSeries(t)
+
+	// An edit of this occurrence only keeps the series fields.
+	data.Name = "Report for the board"
+	if data.editSeries(api.RecurrenceScope_RECURRENCE_SCOPE_THIS) {
+		t.Fatalf("editSeries(THIS) reported a series edit")
+	}
+	if got := data.occurrenceAfter(*data.DueTime).Name; got != "Weekly report" {
+		t.Fatalf("next occurrence after a THIS edit is named %q, want %q", got, "Weekly report")
+	}
+
+	// An edit of the series changes the other and the next occurrences.
+	data.Name = "Weekly status"
+	data.Desc = "Send the status"
+	data.Priority = api.Priority_PRIORITY_HIGH
+	data.Labels = []string{"status"}
+	data.StoryPoints = 2
+	data.Recurrence = "FREQ=DAILY"
+	if !data.editSeries(api.RecurrenceScope_RECURRENCE_SCOPE_SERIES) {
+		t.Fatalf("editSeries(SERIES) did not report a series edit")
+	}
+	want := &seriesFields{
+		Name:        "Weekly status",
+		Desc:        "Send the status",
+		Priority:    api.Priority_PRIORITY_HIGH,
+		Labels:      []string{"status"},
+		Recurrence:  "FREQ=DAILY",
+		StoryPoints: 2,
+	}
+	if !reflect.DeepEqual(data.Series, want) {
+		t.Fatalf("series = %+v, want %+v", data.Series, want)
+	}
+
+	sibling.applySeries(data.sharedFields())
+	if !reflect.DeepEqual(sibling.ownSeriesFields(), want) {
+		t.Errorf("sibling = %+v, want %+v", sibling.ownSeriesFields(), want)
+	}
+
+	next := data.occurrenceAfter(data.DueTime.AddDate(0, 0, 1))
+	if !reflect.DeepEqual(next.ownSeriesFields(), want) {
+		t.Errorf("next occurrence = %+v, want %+v", next.ownSeriesFields(), want)
+	}
+	if !reflect.DeepEqual(next.Series, want) {
+		t.Errorf("next occurrence series = %+v, want %+v", next.Series, want)
+	}
+	if next.SeriesID != data.SeriesID || next.Status != api.Status_STATUS_TODO {
+		t.Errorf("next occurrence is in series %v with status %v", next.SeriesID.Hex(), next.Status)
+	}
+}
+
+func TestEditSeriesNotRecurring(t *testing.T) {
+	data := &task{ID: primitive.NewObjectID(), Name: "One-off"}
+	if data.editSeries(api.RecurrenceScope_RECURRENCE_SCOPE_SERIES) {
+		t.Fatalf("editSeries reported a series edit for a task without a series")
+	}
+	if data.Series != nil {
+		t.Fatalf("series = %+v, want nil", data.Series)
+	}
+}
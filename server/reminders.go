@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var (
+	defaultReminderInterval = 10 * time.Second
+	defaultReminderLease    = time.Minute
+	reminderInterval        = defaultReminderInterval
+	reminderLease           = defaultReminderLease
+)
+
+type reminder struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	RemindTime *time.Time         `bson:"remind_time,omitempty"`
+	BeforeDue  time.Duration      `bson:"before_due,omitempty"`
+	FireTime   *time.Time         `bson:"fire_time,omitempty"`
+	SentTime   *time.Time         `bson:"sent_time,omitempty"`
+
+	// Lease of the scheduler replica that is delivering the reminder.
+	LeaseToken primitive.ObjectID `bson:"lease_token,omitempty"`
+	LeaseUntil *time.Time         `bson:"lease_until,omitempty"`
+}
+
+func getReminderGRPC(data *reminder) *api.Reminder {
+	r := &api.Reminder{
+		Id:       data.ID.Hex(),
+		FireTime: timeToProto(data.FireTime),
+		SentTime: timeToProto(data.SentTime),
+	}
+	if data.RemindTime != nil {
+		r.When = &api.Reminder_RemindTime{RemindTime: timeToProto(data.RemindTime)}
+	} else {
+		r.When = &api.Reminder_BeforeDue{BeforeDue: durationpb.New(data.BeforeDue)}
+	}
+	return r
+}
+
+func getRemindersGRPC(list []reminder) []*api.Reminder {
+	var res []*api.Reminder
+	for i := range list {
+		res = append(res, getReminderGRPC(&list[i]))
+	}
+	return res
+}
+
+// fireTime computes when a reminder fires for a task due at due.
+func (r *reminder) fireTime(due *time.Time) *time.Time {
+	if r.RemindTime != nil {
+		return r.RemindTime
+	}
+	if due == nil {
+		return nil
+	}
+	t := due.Add(-r.BeforeDue)
+	return &t
+}
+
+// setReminders replaces the reminders of data with those requested. An
+// existing reminder keeps its delivery state unless its fire time moves.
+func setReminders(data *task, list []*api.Reminder) error {
+	old := make(map[string]reminder, len(data.Reminders))
+	for _, r := range data.Reminders {
+		old[r.ID.Hex()] = r
+	}
+
+	res := make([]reminder, 0, len(list))
+	for _, r := range list {
+		var next reminder
+		switch w := r.GetWhen().(type) {
+		case *api.Reminder_RemindTime:
+			if err := w.RemindTime.CheckValid(); err != nil {
+				return status.Errorf(
+					codes.InvalidArgument,
+					"[ERROR] invalid reminder time: %v", err,
+				)
+			}
+			next.RemindTime = timeFromProto(w.RemindTime)
+		case *api.Reminder_BeforeDue:
+			if err := w.BeforeDue.CheckValid(); err != nil || w.BeforeDue.AsDuration() < 0 {
+				return status.Errorf(
+					codes.InvalidArgument,
+					"[ERROR] reminder offset must be a non-negative duration",
+				)
+			}
+			next.BeforeDue = w.BeforeDue.AsDuration()
+		default:
+			return status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] reminder needs a remind time or an offset before due",
+			)
+		}
+		next.FireTime = next.fireTime(data.DueTime)
+
+		prev, ok := old[r.GetId()]
+		switch {
+		case r.GetId() == "":
+			next.ID = primitive.NewObjectID()
+		case !ok:
+			return status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] unknown reminder ID: %s", r.GetId(),
+			)
+		default:
+			next.ID = prev.ID
+			if sameTime(prev.FireTime, next.FireTime) {
+				next.SentTime = prev.SentTime
+				next.LeaseToken = prev.LeaseToken
+				next.LeaseUntil = prev.LeaseUntil
+			}
+		}
+		res = append(res, next)
+	}
+
+	data.Reminders = res
+	return nil
+}
+
+// recurringReminders returns copies of the relative reminders of data for
+// the next occurrence of a recurring task.
+func recurringReminders(data *task, due *time.Time) []reminder {
+	var res []reminder
+	for _, r := range data.Reminders {
+		if r.RemindTime != nil {
+			continue
+		}
+		next := reminder{ID: primitive.NewObjectID(), BeforeDue: r.BeforeDue}
+		next.FireTime = next.fireTime(due)
+		res = append(res, next)
+	}
+	return res
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// notifier delivers fired reminders.
+type notifier interface {
+	Notify(ctx context.Context, t *api.Task, r *api.Reminder) error
+}
+
+// logNotifier delivers reminders to the server log.
+type logNotifier struct{}
+
+func (logNotifier) Notify(_ context.Context, t *api.Task, r *api.Reminder) error {
+	log.Printf("[INFO] reminder %s for task %s %q\n", r.GetId(), t.GetId(), t.GetName())
+	return nil
+}
+
+// reminderScheduler fires due reminders. Replicas claim a reminder by
+// taking a lease on it, so each reminder is delivered by one replica only,
+// and delivery state lives in MongoDB so it survives restarts.
+type reminderScheduler struct {
+	notifier notifier
+	interval time.Duration
+	lease    time.Duration
+}
+
+func (s *reminderScheduler) run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			fired, err := s.fireNext(ctx)
+			if err != nil {
+				log.Printf("[ERROR] cannot fire reminder: %v\n", err)
+			}
+			if !fired || err != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// fireNext claims and delivers one due reminder. It reports false when
+// there was nothing to deliver.
+func (s *reminderScheduler) fireNext(ctx context.Context) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	now := time.Now().UTC()
+	token := primitive.NewObjectID()
+	leaseUntil := now.Add(s.lease)
+
+	filter := activeFilter(bson.M{
+		"status": bson.M{"$nin": closedStatuses},
+		"reminders": bson.M{"$elemMatch": bson.M{
+			"sent_time": bson.M{"$exists": false},
+			"fire_time": bson.M{"$lte": now},
+			"$or": bson.A{
+				bson.M{"lease_until": bson.M{"$exists": false}},
+				bson.M{"lease_until": bson.M{"$lt": now}},
+			},
+		}},
+	})
+	update := bson.M{"$set": bson.M{
+		"reminders.$.lease_token": token,
+		"reminders.$.lease_until": leaseUntil,
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	data := newTask()
+	if err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(data); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var claimed *reminder
+	for i := range data.Reminders {
+		if data.Reminders[i].LeaseToken == token {
+			claimed = &data.Reminders[i]
+		}
+	}
+	if claimed == nil {
+		return false, nil
+	}
+
+	if err := s.notifier.Notify(ctx, getTaskGRPC(data), getReminderGRPC(claimed)); err != nil {
+		// The lease runs out and another attempt picks the reminder up.
+		return false, err
+	}
+
+	_, err := collection.UpdateOne(ctx,
+		bson.M{"_id": data.ID, "reminders": bson.M{"$elemMatch": bson.M{"_id": claimed.ID, "lease_token": token}}},
+		bson.M{
+			"$set":   bson.M{"reminders.$.sent_time": time.Now().UTC()},
+			"$unset": bson.M{"reminders.$.lease_token": "", "reminders.$.lease_until": ""},
+		},
+	)
+	return true, err
+}
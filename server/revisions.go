@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var revisionCollection *mongo.Collection
+
+// unrevisedFields are Task fields that are not part of the diff between
+// revisions because they are derived or bookkeeping values.
+var unrevisedFields = map[protoreflect.Name]bool{
+	"id":       true,
+	"revision": true,
+	"blocked":  true,
+	// Changes with every revision.
+	"update_time": true,
+	// Set by MoveTask and the rank rebalancer.
+	"rank": true,
+	// Derived from status.
+	"complete_time": true,
+	// Changed by time tracking.
+	"tracked_time": true,
+}
+
+type taskRevision struct {
+	ID       primitive.ObjectID `bson:"_id"`
+	TaskID   primitive.ObjectID `bson:"task_id"`
+	Revision int64              `bson:"revision"`
+	Time     time.Time          `bson:"time"`
+	Actor    string             `bson:"actor"`
+	Task     task               `bson:"task"`
+}
+
+// recordRevision stores data as revision data.Revision of the task. It must
+// run in the same transaction as the change.
+func recordRevision(ctx context.Context, data *task) error {
+	_, err := revisionCollection.InsertOne(ctx, taskRevision{
+		ID:       primitive.NewObjectID(),
+		TaskID:   data.ID,
+		Revision: data.Revision,
+		Time:     time.Now().UTC(),
+		Actor:    actorFromContext(ctx),
+		Task:     *data,
+	})
+	if err != nil {
+		return dbError(ctx, err, "cannot write task revision")
+	}
+	return nil
+}
+
+// dropRevisions removes the history of permanently deleted tasks.
+func dropRevisions(ctx context.Context, ids []interface{}) error {
+	if _, err := revisionCollection.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": ids}}); err != nil {
+		return dbError(ctx, err, "cannot delete task revisions")
+	}
+	return nil
+}
+
+func getRevisionGRPC(data *taskRevision, prev *taskRevision) *api.TaskRevision {
+	r := &api.TaskRevision{
+		TaskId:   data.TaskID.Hex(),
+		Revision: data.Revision,
+		Time:     timeToProto(&data.Time),
+		Actor:    data.Actor,
+	}
+	var old *api.Task
+	if prev != nil {
+		old = getTaskGRPC(&prev.Task)
+	}
+	r.Changes = diffTasks(old, getTaskGRPC(&data.Task))
+	return r
+}
+
+// diffTasks lists the fields that differ between two task snapshots; a nil
+// snapshot counts as a task with every field unset.
+func diffTasks(a, b *api.Task) []*api.FieldDiff {
+	if a == nil {
+		a = &api.Task{}
+	}
+	ma, mb := a.ProtoReflect(), b.ProtoReflect()
+
+	var res []*api.FieldDiff
+	fields := mb.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if unrevisedFields[fd.Name()] {
+			continue
+		}
+		va, vb := formatField(ma, fd), formatField(mb, fd)
+		if va != vb {
+			res = append(res, &api.FieldDiff{
+				Field:    string(fd.Name()),
+				OldValue: va,
+				NewValue: vb,
+			})
+		}
+	}
+	return res
+}
+
+// formatField renders a field of m in its JSON form, or "" when unset.
+func formatField(m protoreflect.Message, fd protoreflect.FieldDescriptor) string {
+	if !m.Has(fd) {
+		return ""
+	}
+	// Marshal a copy holding only this field and strip the braces and key.
+	one := m.Type().New()
+	one.Set(fd, m.Get(fd))
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(one.Interface())
+	if err != nil {
+		return fmt.Sprint(m.Get(fd).Interface())
+	}
+	// protojson varies its whitespace between builds, so compact the output
+	// to keep stored and fresh renderings comparable.
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b); err != nil {
+		return string(b)
+	}
+	s := buf.String()
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}")
+	if i := strings.Index(s, ":"); i >= 0 {
+		s = s[i+1:]
+	}
+	return s
+}
+
+func findRevision(ctx context.Context, taskID primitive.ObjectID, revision int64) (*taskRevision, error) {
+	data := &taskRevision{}
+	filter := bson.M{"task_id": taskID, "revision": revision}
+	if err := revisionCollection.FindOne(ctx, filter).Decode(data); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Errorf(
+				codes.NotFound,
+				"[ERROR] cannot find revision %d of task", revision,
+			)
+		}
+		return nil, dbError(ctx, err, "cannot read task revision")
+	}
+	return data, nil
+}
+
+func (*server) ListTaskRevisions(req *api.ListTaskRevisionsRequest, stream api.TaskService_ListTaskRevisionsServer) error {
+
+	log.Println("[INFO] stream list task revisions")
+
+	oid, err := primitive.ObjectIDFromHex(req.GetTaskId())
+	if err != nil {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse task ID",
+		)
+	}
+
+	findCtx, cancel := withQueryTimeout(stream.Context())
+	_, err = findTaskWithAccess(findCtx, oid, api.ShareRole_SHARE_ROLE_VIEWER)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	var prev *taskRevision
+	opts := options.Find().SetSort(bson.D{{Key: "revision", Value: 1}})
+	return streamDocs(stream.Context(), revisionCollection, bson.M{"task_id": oid}, opts, func(data *taskRevision) error {
+		r := getRevisionGRPC(data, prev)
+		prev = data
+		return stream.Send(&api.ListTaskRevisionsResponse{
+			Revision: r,
+		})
+	})
+}
+
+func (*server) GetTaskRevision(ctx context.Context, req *api.GetTaskRevisionRequest) (*api.GetTaskRevisionResponse, error) {
+
+	log.Println("[INFO] get task revision")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(req.GetTaskId())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse task ID",
+		)
+	}
+
+	if _, err := findTaskWithAccess(ctx, oid, api.ShareRole_SHARE_ROLE_VIEWER); err != nil {
+		return nil, err
+	}
+	data, err := findRevision(ctx, oid, req.GetRevision())
+	if err != nil {
+		return nil, err
+	}
+
+	var prev *taskRevision
+	if compareTo := req.GetCompareTo(); compareTo > 0 {
+		if prev, err = findRevision(ctx, oid, compareTo); err != nil {
+			return nil, err
+		}
+	} else if data.Revision > 1 {
+		// Tasks created before revisions were kept have no earlier history.
+		if prev, err = findRevision(ctx, oid, data.Revision-1); status.Code(err) == codes.NotFound {
+			prev = nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	r := getRevisionGRPC(data, prev)
+	r.Task = getTaskGRPC(&data.Task)
+	return &api.GetTaskRevisionResponse{
+		Revision: r,
+	}, nil
+}
+
+func (*server) RevertTask(ctx context.Context, req *api.RevertTaskRequest) (*api.RevertTaskResponse, error) {
+
+	log.Println("[INFO] revert task")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var res *api.RevertTaskResponse
+	err := inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = revertTask(ctx, req)
+		return err
+	})
+	return res, err
+}
+
+// revertTask restores a revision as the new revision of a task.
+func revertTask(ctx context.Context, req *api.RevertTaskRequest) (*api.RevertTaskResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(req.GetTaskId())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse task ID",
+		)
+	}
+
+	filter := activeFilter(bson.M{"_id": oid})
+	data, err := findTask(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccess(ctx, data, api.ShareRole_SHARE_ROLE_EDITOR); err != nil {
+		return nil, err
+	}
+	rev, err := findRevision(ctx, oid, req.GetRevision())
+	if err != nil {
+		return nil, err
+	}
+	before := *data
+
+	old := rev.Task
+	if err := checkTransition(data, old.Status, req.GetIgnoreBlockers()); err != nil {
+		return nil, err
+	}
+	if err := checkAssignee(ctx, data, old.AssigneeID); err != nil {
+		return nil, err
+	}
+	data.Name = old.Name
+	data.Desc = old.Desc
+	data.Priority = old.Priority
+	data.DueTime = old.DueTime
+	data.Labels = old.Labels
+	data.Recurrence = old.Recurrence
+	data.RecurrenceStart = old.RecurrenceStart
+	data.AssigneeID = old.AssigneeID
+	data.StoryPoints = old.StoryPoints
+	data.UpdateTime = time.Now().UTC()
+	setStatus(data, old.Status, data.UpdateTime)
+	if !req.GetIgnoreWipLimits() {
+		if err := checkWIPLimits(ctx, &before, data); err != nil {
+			return nil, err
+		}
+	}
+	// Reminders stay, but relative ones follow the restored due time.
+	if err := setReminders(data, getRemindersGRPC(data.Reminders)); err != nil {
+		return nil, err
+	}
+	data.Revision++
+	data.EventSeq++
+
+	if _, err := collection.ReplaceOne(ctx, filter, data); err != nil {
+		return nil, dbError(ctx, err, "cannot update object in MongoDB")
+	}
+	if before.Status != data.Status {
+		if err := refreshDependents(ctx, oid); err != nil {
+			return nil, err
+		}
+	}
+	if err := recordTaskEvent(ctx, eventTaskUpdated, data); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, oid, &before, data); err != nil {
+		return nil, err
+	}
+	if err := recordRevision(ctx, data); err != nil {
+		return nil, err
+	}
+
+	return &api.RevertTaskResponse{
+		Task: getTaskGRPC(data),
+	}, nil
+}
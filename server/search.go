@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// snippetRadius is how many characters of context a description highlight
+// keeps on each side of the first match.
+const snippetRadius = 40
+
+type searchHit struct {
+	task  `bson:",inline"`
+	Score float64 `bson:"score"`
+}
+
+func (*server) SearchTasks(ctx context.Context, req *api.SearchTasksRequest) (*api.SearchTasksResponse, error) {
+
+	log.Println("[INFO] search tasks")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	query := strings.TrimSpace(req.GetQuery())
+	if query == "" {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] query must not be empty",
+		)
+	}
+	limit, err := pageSize(req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	offset, err := decodeOffsetToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	score := bson.M{"$meta": "textScore"}
+	filter := visibleFilter(ctx, activeFilter(bson.M{"$text": bson.M{"$search": query}}))
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}}).
+		SetSkip(offset).
+		SetLimit(limit + 1).
+		SetMaxTime(queryTimeout)
+
+	cur, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, dbError(ctx, err, "cannot search tasks in MongoDB")
+	}
+	var hits []searchHit
+	if err := cur.All(ctx, &hits); err != nil {
+		return nil, dbError(ctx, err, "cannot search tasks in MongoDB")
+	}
+
+	res := &api.SearchTasksResponse{}
+	if int64(len(hits)) > limit {
+		hits = hits[:limit]
+		res.NextPageToken = encodeOffsetToken(offset + limit)
+	}
+
+	terms := searchTerms(query)
+	for i := range hits {
+		h := &hits[i]
+		r := &api.SearchResult{
+			Task:  getTaskGRPC(&h.task),
+			Score: h.Score,
+		}
+		if s, ok := highlight(h.Name, terms, 0); ok {
+			r.Highlights = append(r.Highlights, &api.Highlight{Field: "name", Snippet: s})
+		}
+		if s, ok := highlight(h.Desc, terms, snippetRadius); ok {
+			r.Highlights = append(r.Highlights, &api.Highlight{Field: "desc", Snippet: s})
+		}
+		res.Results = append(res.Results, r)
+	}
+
+	return res, nil
+}
+
+// searchTerms extracts the lower-cased words of a text search query,
+// skipping excluded (-word) terms.
+func searchTerms(query string) []string {
+	var terms []string
+	for _, f := range strings.Fields(query) {
+		if strings.HasPrefix(f, "-") {
+			continue
+		}
+		terms = append(terms, splitWords(strings.ToLower(f))...)
+	}
+	return terms
+}
+
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// highlight wraps the words of text that start with one of terms in
+// <em></em>. A positive radius trims the text to that many characters
+// around the first match. It reports false when nothing matched.
+func highlight(text string, terms []string, radius int) (string, bool) {
+	runes := []rune(text)
+	var b strings.Builder
+	first, last := -1, -1
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		inWord := i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]))
+		if inWord {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			if matchesTerm(strings.ToLower(string(runes[start:i])), terms) {
+				if first < 0 {
+					first = start
+				}
+				last = i
+			}
+			start = -1
+		}
+	}
+	if first < 0 {
+		return "", false
+	}
+
+	from, to := 0, len(runes)
+	if radius > 0 {
+		if first-radius > from {
+			from = first - radius
+		}
+		if last+radius < to {
+			to = last + radius
+		}
+	}
+	if from > 0 {
+		b.WriteString("…")
+	}
+
+	start = -1
+	for i := from; i <= to; i++ {
+		inWord := i < to && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]))
+		if inWord {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			word := string(runes[start:i])
+			if matchesTerm(strings.ToLower(word), terms) {
+				b.WriteString("<em>" + word + "</em>")
+			} else {
+				b.WriteString(word)
+			}
+			start = -1
+		}
+		if i < to {
+			b.WriteRune(runes[i])
+		}
+	}
+
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
+
+func matchesTerm(word string, terms []string) bool {
+	for _, t := range terms {
+		if strings.HasPrefix(word, t) || strings.HasPrefix(t, word) && len(word) >= 3 {
+			return true
+		}
+	}
+	return false
+}
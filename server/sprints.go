@@ -0,0 +1,449 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxSprintDays bounds the length of a sprint and so of its burndown.
+const maxSprintDays = 366
+
+var sprintCollection *mongo.Collection
+
+type sprint struct {
+	ID        primitive.ObjectID   `bson:"_id,omitempty"`
+	Name      string               `bson:"name"`
+	Goal      string               `bson:"goal,omitempty"`
+	StartTime time.Time            `bson:"start_time"`
+	EndTime   time.Time            `bson:"end_time"`
+	TaskIDs   []primitive.ObjectID `bson:"task_ids"`
+	OwnerID   string               `bson:"owner_id,omitempty"`
+}
+
+func getSprintGRPC(data *sprint) *api.Sprint {
+	return &api.Sprint{
+		Id:        data.ID.Hex(),
+		Name:      data.Name,
+		Goal:      data.Goal,
+		StartTime: timeToProto(&data.StartTime),
+		EndTime:   timeToProto(&data.EndTime),
+		TaskIds:   hexIDs(data.TaskIDs),
+		OwnerId:   data.OwnerID,
+	}
+}
+
+func validateSprint(s *api.Sprint) error {
+	if strings.TrimSpace(s.GetName()) == "" {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] sprint name must not be empty",
+		)
+	}
+	if s.GetStartTime() == nil || s.GetEndTime() == nil {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] sprint start and end times are required",
+		)
+	}
+	d := s.GetEndTime().AsTime().Sub(s.GetStartTime().AsTime())
+	if d <= 0 {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] sprint must end after it starts",
+		)
+	}
+	if d > maxSprintDays*24*time.Hour {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] sprint must not be longer than %d days", maxSprintDays,
+		)
+	}
+	return nil
+}
+
+func findSprint(ctx context.Context, id string) (*sprint, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse sprint ID",
+		)
+	}
+
+	data := &sprint{}
+	if err := sprintCollection.FindOne(ctx, bson.M{"_id": oid}).Decode(data); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Errorf(
+				codes.NotFound,
+				"[ERROR] cannot find sprint with ID: %v", err,
+			)
+		}
+		return nil, dbError(ctx, err, "cannot read sprint from MongoDB")
+	}
+	return data, nil
+}
+
+// sprintTaskIDs parses the IDs of tasks added to or removed from a sprint.
+// Tasks being added must be active and editable by the caller.
+func sprintTaskIDs(ctx context.Context, ids []string, add bool) ([]primitive.ObjectID, error) {
+	if len(ids) == 0 {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] task IDs are required",
+		)
+	}
+	res := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] cannot parse task ID %q", id,
+			)
+		}
+		if add {
+			t, err := findTask(ctx, activeFilter(bson.M{"_id": oid}))
+			if err != nil {
+				return nil, err
+			}
+			if err := checkAccess(ctx, t, api.ShareRole_SHARE_ROLE_EDITOR); err != nil {
+				return nil, err
+			}
+		}
+		res = append(res, oid)
+	}
+	return res, nil
+}
+
+// updateSprintTasks applies update to the task list of a sprint and
+// returns the updated sprint.
+func updateSprintTasks(ctx context.Context, id string, update bson.M) (*sprint, error) {
+	data, err := findSprint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOwner(ctx, data.OwnerID, "sprint", data.ID); err != nil {
+		return nil, err
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := sprintCollection.FindOneAndUpdate(ctx, bson.M{"_id": data.ID}, update, opts).Decode(data); err != nil {
+		return nil, dbError(ctx, err, "cannot update sprint in MongoDB")
+	}
+	return data, nil
+}
+
+// dropSprintTasks removes permanently deleted tasks from their sprints.
+func dropSprintTasks(ctx context.Context, ids []interface{}) error {
+	update := bson.M{"$pull": bson.M{"task_ids": bson.M{"$in": ids}}}
+	if _, err := sprintCollection.UpdateMany(ctx, bson.M{"task_ids": bson.M{"$in": ids}}, update); err != nil {
+		return dbError(ctx, err, "cannot remove tasks from sprints")
+	}
+	return nil
+}
+
+type sprintServer struct {
+	api.SprintServiceServer
+}
+
+func (*sprintServer) CreateSprint(ctx context.Context, req *api.CreateSprintRequest) (*api.CreateSprintResponse, error) {
+
+	log.Println("[INFO] create sprint")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	s := req.GetSprint()
+	if err := validateSprint(s); err != nil {
+		return nil, err
+	}
+
+	data := sprint{
+		Name:      s.GetName(),
+		Goal:      s.GetGoal(),
+		StartTime: s.GetStartTime().AsTime().UTC(),
+		EndTime:   s.GetEndTime().AsTime().UTC(),
+		TaskIDs:   []primitive.ObjectID{},
+		OwnerID:   actorFromContext(ctx),
+	}
+	res, err := sprintCollection.InsertOne(ctx, data)
+	if err != nil {
+		return nil, dbError(ctx, err, "cannot create sprint in MongoDB")
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, status.Errorf(
+			codes.Internal,
+			"[ERROR] Cannot convert to OID",
+		)
+	}
+
+	data.ID = oid
+	return &api.CreateSprintResponse{
+		Sprint: getSprintGRPC(&data),
+	}, nil
+}
+
+func (*sprintServer) GetSprint(ctx context.Context, req *api.GetSprintRequest) (*api.GetSprintResponse, error) {
+
+	log.Println("[INFO] get sprint")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data, err := findSprint(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.GetSprintResponse{
+		Sprint: getSprintGRPC(data),
+	}, nil
+}
+
+func (*sprintServer) UpdateSprint(ctx context.Context, req *api.UpdateSprintRequest) (*api.UpdateSprintResponse, error) {
+
+	log.Println("[INFO] update sprint")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	s := req.GetSprint()
+	if err := validateSprint(s); err != nil {
+		return nil, err
+	}
+	data, err := findSprint(ctx, s.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOwner(ctx, data.OwnerID, "sprint", data.ID); err != nil {
+		return nil, err
+	}
+
+	data.Name = s.GetName()
+	data.Goal = s.GetGoal()
+	data.StartTime = s.GetStartTime().AsTime().UTC()
+	data.EndTime = s.GetEndTime().AsTime().UTC()
+	update := bson.M{"$set": bson.M{
+		"name":       data.Name,
+		"goal":       data.Goal,
+		"start_time": data.StartTime,
+		"end_time":   data.EndTime,
+	}}
+	if _, err := sprintCollection.UpdateOne(ctx, bson.M{"_id": data.ID}, update); err != nil {
+		return nil, dbError(ctx, err, "cannot update sprint in MongoDB")
+	}
+
+	return &api.UpdateSprintResponse{
+		Sprint: getSprintGRPC(data),
+	}, nil
+}
+
+func (*sprintServer) DeleteSprint(ctx context.Context, req *api.DeleteSprintRequest) (*api.DeleteSprintResponse, error) {
+
+	log.Println("[INFO] delete sprint")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data, err := findSprint(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOwner(ctx, data.OwnerID, "sprint", data.ID); err != nil {
+		return nil, err
+	}
+	if _, err := sprintCollection.DeleteOne(ctx, bson.M{"_id": data.ID}); err != nil {
+		return nil, dbError(ctx, err, "cannot delete sprint in MongoDB")
+	}
+
+	return &api.DeleteSprintResponse{
+		Id: req.GetId(),
+	}, nil
+}
+
+func (*sprintServer) ListSprints(_ *api.ListSprintsRequest, stream api.SprintService_ListSprintsServer) error {
+
+	log.Println("[INFO] stream list sprints")
+
+	opts := options.Find().SetSort(bson.D{{Key: "start_time", Value: -1}, {Key: "_id", Value: -1}})
+	return streamDocs(stream.Context(), sprintCollection, bson.M{}, opts, func(data *sprint) error {
+		return stream.Send(&api.ListSprintsResponse{
+			Sprint: getSprintGRPC(data),
+		})
+	})
+}
+
+func (*sprintServer) AddSprintTasks(ctx context.Context, req *api.AddSprintTasksRequest) (*api.AddSprintTasksResponse, error) {
+
+	log.Println("[INFO] add sprint tasks")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	ids, err := sprintTaskIDs(ctx, req.GetTaskIds(), true)
+	if err != nil {
+		return nil, err
+	}
+	data, err := updateSprintTasks(ctx, req.GetSprintId(), bson.M{
+		"$addToSet": bson.M{"task_ids": bson.M{"$each": ids}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.AddSprintTasksResponse{
+		Sprint: getSprintGRPC(data),
+	}, nil
+}
+
+func (*sprintServer) RemoveSprintTasks(ctx context.Context, req *api.RemoveSprintTasksRequest) (*api.RemoveSprintTasksResponse, error) {
+
+	log.Println("[INFO] remove sprint tasks")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	ids, err := sprintTaskIDs(ctx, req.GetTaskIds(), false)
+	if err != nil {
+		return nil, err
+	}
+	data, err := updateSprintTasks(ctx, req.GetSprintId(), bson.M{
+		"$pullAll": bson.M{"task_ids": ids},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.RemoveSprintTasksResponse{
+		Sprint: getSprintGRPC(data),
+	}, nil
+}
+
+// taskHistory is the known states of a task, oldest first.
+type taskHistory struct {
+	current   *task
+	revisions []taskRevision
+}
+
+// at returns the state of the task at time t, or nil if it did not exist
+// yet. Before its first recorded revision a task is taken to have been as
+// it is now, done only if it was completed by then.
+func (h *taskHistory) at(t time.Time) *task {
+	var state *task
+	for i := range h.revisions {
+		if h.revisions[i].Time.After(t) {
+			break
+		}
+		state = &h.revisions[i].Task
+	}
+	if state != nil {
+		return state
+	}
+	if h.current.CreateTime.After(t) {
+		return nil
+	}
+	old := *h.current
+	if old.Status == api.Status_STATUS_DONE && (old.CompleteTime == nil || old.CompleteTime.After(t)) {
+		old.Status = api.Status_STATUS_TODO
+	}
+	return &old
+}
+
+// remainingPoints sums the story points of the open tasks at time t.
+func remainingPoints(tasks []*taskHistory, t time.Time) int64 {
+	var n int64
+	for _, h := range tasks {
+		state := h.at(t)
+		if state == nil || state.Status == api.Status_STATUS_DONE || state.Status == api.Status_STATUS_CANCELLED {
+			continue
+		}
+		n += int64(state.StoryPoints)
+	}
+	return n
+}
+
+func (*sprintServer) GetBurndown(ctx context.Context, req *api.GetBurndownRequest) (*api.GetBurndownResponse, error) {
+
+	log.Println("[INFO] get burndown")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data, err := findSprint(ctx, req.GetSprintId())
+	if err != nil {
+		return nil, err
+	}
+
+	// Tasks the caller cannot read do not count.
+	tasks := make(map[primitive.ObjectID]*taskHistory)
+	res := &api.GetBurndownResponse{}
+	filter := visibleFilter(ctx, activeFilter(bson.M{"_id": bson.M{"$in": data.TaskIDs}}))
+	err = streamTasks(ctx, filter, nil, func(t *task) error {
+		tasks[t.ID] = &taskHistory{current: t}
+		res.TotalPoints += int64(t.StoryPoints)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	filter = bson.M{
+		"task_id": bson.M{"$in": data.TaskIDs},
+		"time":    bson.M{"$lt": data.EndTime},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "task_id", Value: 1}, {Key: "revision", Value: 1}})
+	err = streamDocs(ctx, revisionCollection, filter, opts, func(rev *taskRevision) error {
+		if h, ok := tasks[rev.TaskID]; ok {
+			h.revisions = append(h.revisions, *rev)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	histories := make([]*taskHistory, 0, len(tasks))
+	for _, h := range tasks {
+		histories = append(histories, h)
+	}
+
+	now := time.Now().UTC()
+	first := data.StartTime.Truncate(24 * time.Hour)
+	last := data.EndTime.Add(-time.Nanosecond).Truncate(24 * time.Hour)
+	days := int(last.Sub(first)/(24*time.Hour)) + 1
+	for i := 0; i < days; i++ {
+		day := first.Add(time.Duration(i) * 24 * time.Hour)
+		if day.After(now) {
+			break
+		}
+		end := day.Add(24 * time.Hour)
+		if end.After(now) {
+			end = now
+		}
+		if end.After(data.EndTime) {
+			end = data.EndTime
+		}
+		ideal := float64(res.TotalPoints)
+		if days > 1 {
+			ideal *= 1 - float64(i)/float64(days-1)
+		}
+		res.Days = append(res.Days, &api.BurndownDay{
+			Date:            day.Format(dateLayout),
+			RemainingPoints: remainingPoints(histories, end),
+			IdealPoints:     ideal,
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultDayRange = 30 * 24 * time.Hour
+	maxRangeDays    = 366
+	dateLayout      = "2006-01-02"
+)
+
+// statBucket is a group produced by the stats pipeline.
+type statBucket[T any] struct {
+	ID T     `bson:"_id"`
+	N  int64 `bson:"n"`
+}
+
+type taskStats struct {
+	Status      []statBucket[api.Status]   `bson:"status"`
+	Priority    []statBucket[api.Priority] `bson:"priority"`
+	Label       []statBucket[string]       `bson:"label"`
+	Assignee    []statBucket[string]       `bson:"assignee"`
+	Overdue     []statBucket[interface{}]  `bson:"overdue"`
+	Completions []statBucket[string]       `bson:"completions"`
+}
+
+// countBy groups the tasks of a facet by key, most common first.
+func countBy(key interface{}) bson.A {
+	return bson.A{
+		bson.M{"$group": bson.M{"_id": key, "n": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "n", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+}
+
+// dayRange widens a requested time range to whole UTC days, defaulting to
+// the 30 days before now.
+func dayRange(startTime, endTime *timestamppb.Timestamp, now time.Time) (start, end time.Time, err error) {
+	end = now
+	if endTime != nil {
+		end = endTime.AsTime()
+	}
+	start = end.Add(-defaultDayRange)
+	if startTime != nil {
+		start = startTime.AsTime()
+	}
+	start = start.UTC().Truncate(24 * time.Hour)
+	end = end.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	if !start.Before(end) {
+		return start, end, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] start_time must be before end_time",
+		)
+	}
+	if end.Sub(start) > maxRangeDays*24*time.Hour {
+		return start, end, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] time range must not exceed %d days", maxRangeDays,
+		)
+	}
+	return start, end, nil
+}
+
+func (*server) GetTaskStats(ctx context.Context, req *api.GetTaskStatsRequest) (*api.GetTaskStatsResponse, error) {
+
+	log.Println("[INFO] get task stats")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	now := time.Now().UTC()
+	start, end, err := dayRange(req.GetStartTime(), req.GetEndTime(), now)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M{}
+	if req.GetProjectId() != "" {
+		oid, err := primitive.ObjectIDFromHex(req.GetProjectId())
+		if err != nil {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] cannot parse project ID",
+			)
+		}
+		filter["project_id"] = oid
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: visibleFilter(ctx, activeFilter(filter))}},
+		{{Key: "$facet", Value: bson.M{
+			"status":   countBy("$status"),
+			"priority": countBy("$priority"),
+			"label": append(bson.A{
+				bson.M{"$unwind": "$labels"},
+			}, countBy("$labels")...),
+			"assignee": countBy(bson.M{"$ifNull": bson.A{"$assignee_id", ""}}),
+			"overdue": append(bson.A{
+				bson.M{"$match": bson.M{
+					"due_time": bson.M{"$lt": now},
+					"status":   bson.M{"$nin": closedStatuses},
+				}},
+			}, countBy(nil)...),
+			"completions": append(bson.A{
+				bson.M{"$match": bson.M{
+					"status":        api.Status_STATUS_DONE,
+					"complete_time": bson.M{"$gte": start, "$lt": end},
+				}},
+			}, countBy(bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$complete_time"}})...),
+		}}},
+	}
+	cur, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, dbError(ctx, err, "cannot aggregate task stats")
+	}
+	var stats []taskStats
+	if err := cur.All(ctx, &stats); err != nil {
+		return nil, dbError(ctx, err, "cannot aggregate task stats")
+	}
+	if len(stats) == 0 {
+		stats = append(stats, taskStats{})
+	}
+	s := stats[0]
+
+	res := &api.GetTaskStatsResponse{}
+	for _, b := range s.Status {
+		res.Total += b.N
+		if b.ID == api.Status_STATUS_DONE {
+			res.Done = b.N
+		}
+		res.ByStatus = append(res.ByStatus, &api.StatusCount{Status: b.ID, Count: b.N})
+	}
+	res.NotDone = res.Total - res.Done
+	for _, b := range s.Priority {
+		res.ByPriority = append(res.ByPriority, &api.PriorityCount{Priority: b.ID, Count: b.N})
+	}
+	for _, b := range s.Label {
+		res.ByLabel = append(res.ByLabel, &api.LabelCount{Label: b.ID, Count: b.N})
+	}
+	for _, b := range s.Assignee {
+		res.ByAssignee = append(res.ByAssignee, &api.AssigneeCount{AssigneeId: b.ID, Count: b.N})
+	}
+	for _, b := range s.Overdue {
+		res.Overdue += b.N
+	}
+
+	completions := make(map[string]int64, len(s.Completions))
+	for _, b := range s.Completions {
+		completions[b.ID] = b.N
+	}
+	for day := start; day.Before(end); day = day.Add(24 * time.Hour) {
+		date := day.Format(dateLayout)
+		res.CompletionsPerDay = append(res.CompletionsPerDay, &api.DayCount{Date: date, Count: completions[date]})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// transitions lists the statuses a task may move to from each status.
+var transitions = map[api.Status][]api.Status{
+	api.Status_STATUS_TODO: {
+		api.Status_STATUS_IN_PROGRESS,
+		api.Status_STATUS_BLOCKED,
+		api.Status_STATUS_DONE,
+		api.Status_STATUS_CANCELLED,
+	},
+	api.Status_STATUS_IN_PROGRESS: {
+		api.Status_STATUS_TODO,
+		api.Status_STATUS_BLOCKED,
+		api.Status_STATUS_DONE,
+		api.Status_STATUS_CANCELLED,
+	},
+	api.Status_STATUS_BLOCKED: {
+		api.Status_STATUS_TODO,
+		api.Status_STATUS_IN_PROGRESS,
+		api.Status_STATUS_CANCELLED,
+	},
+	api.Status_STATUS_DONE: {
+		api.Status_STATUS_TODO,
+	},
+	api.Status_STATUS_CANCELLED: {
+		api.Status_STATUS_TODO,
+	},
+}
+
+var sortFields = map[api.TaskSortField]string{
+	api.TaskSortField_TASK_SORT_FIELD_PRIORITY: "priority",
+	api.TaskSortField_TASK_SORT_FIELD_DUE_TIME: "due_time",
+	api.TaskSortField_TASK_SORT_FIELD_STATUS:   "status",
+}
+
+func validStatus(s api.Status) bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+func validPriority(p api.Priority) bool {
+	_, ok := api.Priority_name[int32(p)]
+	return ok
+}
+
+// canTransition reports whether a task may move from one status to another.
+func canTransition(from, to api.Status) bool {
+	if from == to {
+		return true
+	}
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTransition fails with FailedPrecondition when data may not move to
+// status st. A blocked task can only be completed with ignoreBlockers.
+func checkTransition(data *task, st api.Status, ignoreBlockers bool) error {
+	if !canTransition(data.Status, st) {
+		return status.Errorf(
+			codes.FailedPrecondition,
+			"[ERROR] cannot move task from %v to %v", data.Status, st,
+		)
+	}
+	if st == api.Status_STATUS_DONE && data.Status != st && data.Blocked && !ignoreBlockers {
+		return status.Errorf(
+			codes.FailedPrecondition,
+			"[ERROR] task is blocked by unfinished tasks",
+		)
+	}
+	return nil
+}
+
+// setStatus moves data to status st at time now, keeping the fields derived
+// from the status in step.
+func setStatus(data *task, st api.Status, now time.Time) {
+	switch {
+	case st != api.Status_STATUS_DONE:
+		data.CompleteTime = nil
+	case data.Status != st || data.CompleteTime == nil:
+		data.CompleteTime = &now
+	}
+	data.Status = st
+	data.Done = st == api.Status_STATUS_DONE
+}
+
+// requestedStatus works out the status a client asks for. An explicit status
+// wins; otherwise the legacy done flag moves the task to or from DONE.
+func requestedStatus(current api.Status, t *api.Task) (api.Status, error) {
+	if t.GetStatus() != api.Status_STATUS_UNSPECIFIED {
+		if !validStatus(t.GetStatus()) {
+			return 0, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] unknown status: %v", t.GetStatus(),
+			)
+		}
+		return t.GetStatus(), nil
+	}
+	switch {
+	case t.GetDone() && current != api.Status_STATUS_DONE:
+		return api.Status_STATUS_DONE, nil
+	case !t.GetDone() && current == api.Status_STATUS_DONE:
+		return api.Status_STATUS_TODO, nil
+	}
+	return current, nil
+}
+
+func validateTaskFields(t *api.Task) error {
+	if !validPriority(t.GetPriority()) {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] unknown priority: %v", t.GetPriority(),
+		)
+	}
+	if t.GetStoryPoints() < 0 {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] story points must not be negative",
+		)
+	}
+	if t.GetDueTime() != nil {
+		if err := t.GetDueTime().CheckValid(); err != nil {
+			return status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] invalid due time: %v", err,
+			)
+		}
+	}
+	return nil
+}
+
+// listFilter builds the MongoDB filter and sort order for ListTask.
+func listFilter(actor string, req *api.ListTaskRequest) (bson.M, []orderKey, error) {
+	filter := activeFilter(bson.M{})
+
+	if (req.GetAssignedToMe() || req.GetSharedWithMe()) && actor == "" {
+		return nil, nil, status.Errorf(
+			codes.Unauthenticated,
+			"[ERROR] %s metadata is required to filter by the caller", userIDHeader,
+		)
+	}
+	if req.GetAssignedToMe() {
+		filter["assignee_id"] = actor
+	}
+	if req.GetSharedWithMe() {
+		filter["acl.user_id"] = actor
+	}
+	if req.GetFilter() != "" {
+		q, err := listFilterQuery(req.GetFilter())
+		if err != nil {
+			return nil, nil, err
+		}
+		filter["$and"] = bson.A{q}
+	}
+
+	if len(req.GetStatuses()) > 0 {
+		var in bson.A
+		for _, s := range req.GetStatuses() {
+			if !validStatus(s) {
+				return nil, nil, status.Errorf(
+					codes.InvalidArgument,
+					"[ERROR] unknown status: %v", s,
+				)
+			}
+			in = append(in, s)
+		}
+		filter["status"] = bson.M{"$in": in}
+	}
+
+	if len(req.GetPriorities()) > 0 {
+		var in bson.A
+		for _, p := range req.GetPriorities() {
+			if !validPriority(p) {
+				return nil, nil, status.Errorf(
+					codes.InvalidArgument,
+					"[ERROR] unknown priority: %v", p,
+				)
+			}
+			in = append(in, p)
+		}
+		filter["priority"] = bson.M{"$in": in}
+	}
+
+	if req.GetProjectId() != "" {
+		oid, err := primitive.ObjectIDFromHex(req.GetProjectId())
+		if err != nil {
+			return nil, nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] cannot parse project ID",
+			)
+		}
+		filter["project_id"] = oid
+	}
+
+	labels := bson.M{}
+	if len(req.GetLabelsAll()) > 0 {
+		allOf, err := cleanLabels(req.GetLabelsAll())
+		if err != nil {
+			return nil, nil, err
+		}
+		labels["$all"] = allOf
+	}
+	if len(req.GetLabelsAny()) > 0 {
+		anyOf, err := cleanLabels(req.GetLabelsAny())
+		if err != nil {
+			return nil, nil, err
+		}
+		labels["$in"] = anyOf
+	}
+	if len(labels) > 0 {
+		filter["labels"] = labels
+	}
+
+	due := bson.M{}
+	if req.GetDueBefore() != nil {
+		due["$lt"] = req.GetDueBefore().AsTime()
+	}
+	if req.GetDueAfter() != nil {
+		due["$gt"] = req.GetDueAfter().AsTime()
+	}
+	if len(due) > 0 {
+		filter["due_time"] = due
+	}
+
+	order, err := listOrder(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return filter, order, nil
+}
+
+// migrateStatus fills in the status of documents written before statuses
+// existed, deriving it from the done flag.
+func migrateStatus(ctx context.Context) error {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	update := bson.A{bson.M{"$set": bson.M{"status": bson.M{"$cond": bson.A{
+		"$done", api.Status_STATUS_DONE, api.Status_STATUS_TODO,
+	}}}}}
+	res, err := collection.UpdateMany(ctx, bson.M{"status": bson.M{"$exists": false}}, update)
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount > 0 {
+		log.Printf("[INFO] migrated status of %d tasks\n", res.ModifiedCount)
+	}
+	return nil
+}
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxTemplateTasks bounds the number of tasks a template creates, all of
+// which InstantiateTemplate writes in a single transaction.
+const maxTemplateTasks = 100
+
+// instantiateTimeouts is how many query timeouts InstantiateTemplate may
+// take, as it creates up to maxTemplateTasks tasks in one call.
+const instantiateTimeouts = 6
+
+// placeholderPattern matches placeholders such as {{version}}.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+var templateCollection *mongo.Collection
+
+type blueprint struct {
+	Name        string         `bson:"name"`
+	Desc        string         `bson:"desc,omitempty"`
+	Priority    api.Priority   `bson:"priority,omitempty"`
+	Labels      []string       `bson:"labels,omitempty"`
+	StoryPoints int32          `bson:"story_points,omitempty"`
+	DueOffset   *time.Duration `bson:"due_offset,omitempty"`
+	Subtasks    []blueprint    `bson:"subtasks,omitempty"`
+}
+
+type taskTemplate struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty"`
+	Name    string             `bson:"name"`
+	Desc    string             `bson:"desc"`
+	Tasks   []blueprint        `bson:"tasks"`
+	OwnerID string             `bson:"owner_id,omitempty"`
+}
+
+func getBlueprintGRPC(b *blueprint) *api.TaskBlueprint {
+	res := &api.TaskBlueprint{
+		Name:        b.Name,
+		Desc:        b.Desc,
+		Priority:    b.Priority,
+		Labels:      b.Labels,
+		StoryPoints: b.StoryPoints,
+	}
+	if b.DueOffset != nil {
+		res.DueOffset = durationpb.New(*b.DueOffset)
+	}
+	for i := range b.Subtasks {
+		res.Subtasks = append(res.Subtasks, getBlueprintGRPC(&b.Subtasks[i]))
+	}
+	return res
+}
+
+func getTemplateGRPC(data *taskTemplate) *api.TaskTemplate {
+	res := &api.TaskTemplate{
+		Id:        data.ID.Hex(),
+		Name:      data.Name,
+		Desc:      data.Desc,
+		Variables: data.variables(),
+		OwnerId:   data.OwnerID,
+	}
+	for i := range data.Tasks {
+		res.Tasks = append(res.Tasks, getBlueprintGRPC(&data.Tasks[i]))
+	}
+	return res
+}
+
+// walkBlueprints calls fn for every blueprint in list, parents before their
+// subtasks.
+func walkBlueprints(list []blueprint, fn func(b *blueprint)) {
+	for i := range list {
+		fn(&list[i])
+		walkBlueprints(list[i].Subtasks, fn)
+	}
+}
+
+// variables lists the placeholders of the template in order of first use.
+func (t *taskTemplate) variables() []string {
+	var res []string
+	seen := make(map[string]bool)
+	add := func(s string) {
+		for _, m := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				res = append(res, m[1])
+			}
+		}
+	}
+	walkBlueprints(t.Tasks, func(b *blueprint) {
+		add(b.Name)
+		add(b.Desc)
+		for _, l := range b.Labels {
+			add(l)
+		}
+	})
+	return res
+}
+
+// blueprintsFromGRPC validates a tree of blueprints and converts it,
+// counting the blueprints in n.
+func blueprintsFromGRPC(list []*api.TaskBlueprint, n *int) ([]blueprint, error) {
+	var res []blueprint
+	for _, b := range list {
+		if *n++; *n > maxTemplateTasks {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] template must not have more than %d tasks", maxTemplateTasks,
+			)
+		}
+		if strings.TrimSpace(b.GetName()) == "" {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] task blueprint name must not be empty",
+			)
+		}
+		if !validPriority(b.GetPriority()) {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] unknown priority: %v", b.GetPriority(),
+			)
+		}
+		if b.GetStoryPoints() < 0 {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] story points must not be negative",
+			)
+		}
+		data := blueprint{
+			Name:        b.GetName(),
+			Desc:        b.GetDesc(),
+			Priority:    b.GetPriority(),
+			Labels:      b.GetLabels(),
+			StoryPoints: b.GetStoryPoints(),
+		}
+		if b.GetDueOffset() != nil {
+			if err := b.GetDueOffset().CheckValid(); err != nil {
+				return nil, status.Errorf(
+					codes.InvalidArgument,
+					"[ERROR] invalid due offset: %v", err,
+				)
+			}
+			d := b.GetDueOffset().AsDuration()
+			data.DueOffset = &d
+		}
+		subtasks, err := blueprintsFromGRPC(b.GetSubtasks(), n)
+		if err != nil {
+			return nil, err
+		}
+		data.Subtasks = subtasks
+		res = append(res, data)
+	}
+	return res, nil
+}
+
+func templateFromGRPC(t *api.TaskTemplate) (*taskTemplate, error) {
+	if strings.TrimSpace(t.GetName()) == "" {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] template name must not be empty",
+		)
+	}
+	if len(t.GetTasks()) == 0 {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] template must have at least one task",
+		)
+	}
+	n := 0
+	tasks, err := blueprintsFromGRPC(t.GetTasks(), &n)
+	if err != nil {
+		return nil, err
+	}
+	return &taskTemplate{
+		Name:  t.GetName(),
+		Desc:  t.GetDesc(),
+		Tasks: tasks,
+	}, nil
+}
+
+func findTemplate(ctx context.Context, id string) (*taskTemplate, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse template ID",
+		)
+	}
+
+	data := &taskTemplate{}
+	if err := templateCollection.FindOne(ctx, bson.M{"_id": oid}).Decode(data); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Errorf(
+				codes.NotFound,
+				"[ERROR] cannot find template with ID: %v", err,
+			)
+		}
+		return nil, dbError(ctx, err, "cannot read template from MongoDB")
+	}
+	return data, nil
+}
+
+type templateServer struct {
+	api.TemplateServiceServer
+}
+
+func (*templateServer) CreateTemplate(ctx context.Context, req *api.CreateTemplateRequest) (*api.CreateTemplateResponse, error) {
+
+	log.Println("[INFO] create template")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data, err := templateFromGRPC(req.GetTemplate())
+	if err != nil {
+		return nil, err
+	}
+	data.OwnerID = actorFromContext(ctx)
+
+	res, err := templateCollection.InsertOne(ctx, data)
+	if err != nil {
+		return nil, dbError(ctx, err, "cannot create template in MongoDB")
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, status.Errorf(
+			codes.Internal,
+			"[ERROR] Cannot convert to OID",
+		)
+	}
+
+	data.ID = oid
+	return &api.CreateTemplateResponse{
+		Template: getTemplateGRPC(data),
+	}, nil
+}
+
+func (*templateServer) GetTemplate(ctx context.Context, req *api.GetTemplateRequest) (*api.GetTemplateResponse, error) {
+
+	log.Println("[INFO] get template")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data, err := findTemplate(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.GetTemplateResponse{
+		Template: getTemplateGRPC(data),
+	}, nil
+}
+
+func (*templateServer) UpdateTemplate(ctx context.Context, req *api.UpdateTemplateRequest) (*api.UpdateTemplateResponse, error) {
+
+	log.Println("[INFO] update template")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	old, err := findTemplate(ctx, req.GetTemplate().GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOwner(ctx, old.OwnerID, "template", old.ID); err != nil {
+		return nil, err
+	}
+	data, err := templateFromGRPC(req.GetTemplate())
+	if err != nil {
+		return nil, err
+	}
+	data.ID = old.ID
+	data.OwnerID = old.OwnerID
+
+	if _, err := templateCollection.ReplaceOne(ctx, bson.M{"_id": data.ID}, data); err != nil {
+		return nil, dbError(ctx, err, "cannot update template in MongoDB")
+	}
+
+	return &api.UpdateTemplateResponse{
+		Template: getTemplateGRPC(data),
+	}, nil
+}
+
+func (*templateServer) DeleteTemplate(ctx context.Context, req *api.DeleteTemplateRequest) (*api.DeleteTemplateResponse, error) {
+
+	log.Println("[INFO] delete template")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data, err := findTemplate(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOwner(ctx, data.OwnerID, "template", data.ID); err != nil {
+		return nil, err
+	}
+	if _, err := templateCollection.DeleteOne(ctx, bson.M{"_id": data.ID}); err != nil {
+		return nil, dbError(ctx, err, "cannot delete template in MongoDB")
+	}
+
+	return &api.DeleteTemplateResponse{
+		Id: req.GetId(),
+	}, nil
+}
+
+func (*templateServer) ListTemplates(_ *api.ListTemplatesRequest, stream api.TemplateService_ListTemplatesServer) error {
+
+	log.Println("[INFO] stream list templates")
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+	return streamDocs(stream.Context(), templateCollection, bson.M{}, opts, func(data *taskTemplate) error {
+		return stream.Send(&api.ListTemplatesResponse{
+			Template: getTemplateGRPC(data),
+		})
+	})
+}
+
+func (*templateServer) InstantiateTemplate(ctx context.Context, req *api.InstantiateTemplateRequest) (*api.InstantiateTemplateResponse, error) {
+
+	log.Println("[INFO] instantiate template")
+
+	ctx, cancel := context.WithTimeout(ctx, instantiateTimeouts*queryTimeout)
+	defer cancel()
+
+	data, err := findTemplate(ctx, req.GetTemplateId())
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, v := range data.variables() {
+		if _, ok := req.GetVariables()[v]; !ok {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] missing template variables: %s", strings.Join(missing, ", "),
+		)
+	}
+	base := time.Now().UTC()
+	if req.GetBaseTime() != nil {
+		if err := req.GetBaseTime().CheckValid(); err != nil {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] invalid base time: %v", err,
+			)
+		}
+		base = req.GetBaseTime().AsTime()
+	}
+
+	i := &instantiation{
+		vars:      req.GetVariables(),
+		projectID: req.GetProjectId(),
+		base:      base,
+		ignoreWIP: req.GetIgnoreWipLimits(),
+	}
+	var res *api.InstantiateTemplateResponse
+	err = inTransaction(ctx, func(ctx context.Context) error {
+		i.tasks = nil
+		if err := i.create(ctx, data.Tasks, req.GetParentId()); err != nil {
+			return err
+		}
+		res = &api.InstantiateTemplateResponse{Tasks: i.tasks}
+		return nil
+	})
+	return res, err
+}
+
+// instantiation creates the tasks of a template.
+type instantiation struct {
+	vars      map[string]string
+	projectID string
+	base      time.Time
+	ignoreWIP bool
+	tasks     []*api.Task
+}
+
+// expand replaces the placeholders in s with their values.
+func (i *instantiation) expand(s string) string {
+	return placeholderPattern.ReplaceAllStringFunc(s, func(m string) string {
+		return i.vars[placeholderPattern.FindStringSubmatch(m)[1]]
+	})
+}
+
+// create creates a task for each blueprint in list under parentID, followed
+// by its subtasks.
+func (i *instantiation) create(ctx context.Context, list []blueprint, parentID string) error {
+	for _, b := range list {
+		t := &api.Task{
+			Name:        i.expand(b.Name),
+			Desc:        i.expand(b.Desc),
+			Priority:    b.Priority,
+			ProjectId:   i.projectID,
+			ParentId:    parentID,
+			StoryPoints: b.StoryPoints,
+		}
+		for _, l := range b.Labels {
+			t.Labels = append(t.Labels, i.expand(l))
+		}
+		if b.DueOffset != nil {
+			t.DueTime = timestamppb.New(i.base.Add(*b.DueOffset))
+		}
+
+		res, err := createTask(ctx, &api.CreateTaskRequest{Task: t, IgnoreWipLimits: i.ignoreWIP})
+		if err != nil {
+			return err
+		}
+		i.tasks = append(i.tasks, res.GetTask())
+		if err := i.create(ctx, b.Subtasks, res.GetTask().GetId()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,441 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var timeEntryCollection *mongo.Collection
+
+type timeEntry struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	TaskID    primitive.ObjectID `bson:"task_id"`
+	UserID    string             `bson:"user_id"`
+	StartTime time.Time          `bson:"start_time"`
+	EndTime   *time.Time         `bson:"end_time,omitempty"`
+	Duration  time.Duration      `bson:"duration"`
+	Note      string             `bson:"note,omitempty"`
+	Manual    bool               `bson:"manual,omitempty"`
+	// Running is only stored while the timer runs, for the unique index
+	// that allows one running timer per user.
+	Running bool `bson:"running,omitempty"`
+}
+
+func getTimeEntryGRPC(data *timeEntry) *api.TimeEntry {
+	res := &api.TimeEntry{
+		Id:        data.ID.Hex(),
+		TaskId:    data.TaskID.Hex(),
+		UserId:    data.UserID,
+		StartTime: timeToProto(&data.StartTime),
+		EndTime:   timeToProto(data.EndTime),
+		Note:      data.Note,
+		Manual:    data.Manual,
+	}
+	if !data.Running {
+		res.Duration = durationpb.New(data.Duration)
+	}
+	return res
+}
+
+// trackedTask loads an active task the caller may track time on.
+func trackedTask(ctx context.Context, id string) (*task, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse task ID",
+		)
+	}
+	data, err := findTask(ctx, activeFilter(bson.M{"_id": oid}))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccess(ctx, data, api.ShareRole_SHARE_ROLE_EDITOR); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// addTrackedTime adds d to the total tracked time of a task.
+func addTrackedTime(ctx context.Context, taskID primitive.ObjectID, d time.Duration) error {
+	update := bson.M{"$inc": bson.M{"tracked_time": d}}
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": taskID}, update); err != nil {
+		return dbError(ctx, err, "cannot update tracked time")
+	}
+	return nil
+}
+
+// dropTimeEntries removes the time entries of permanently deleted tasks.
+func dropTimeEntries(ctx context.Context, ids []interface{}) error {
+	if _, err := timeEntryCollection.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": ids}}); err != nil {
+		return dbError(ctx, err, "cannot delete task time entries")
+	}
+	return nil
+}
+
+type timeTrackingServer struct {
+	api.TimeTrackingServiceServer
+}
+
+func (*timeTrackingServer) StartTimer(ctx context.Context, req *api.StartTimerRequest) (*api.StartTimerResponse, error) {
+
+	log.Println("[INFO] start timer")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t, err := trackedTask(ctx, req.GetTaskId())
+	if err != nil {
+		return nil, err
+	}
+
+	running := &timeEntry{}
+	err = timeEntryCollection.FindOne(ctx, bson.M{"user_id": actor, "running": true}).Decode(running)
+	if err == nil {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			"[ERROR] a timer is already running on task %s", running.TaskID.Hex(),
+		)
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, dbError(ctx, err, "cannot read running timer")
+	}
+
+	data := timeEntry{
+		ID:        primitive.NewObjectID(),
+		TaskID:    t.ID,
+		UserID:    actor,
+		StartTime: time.Now().UTC().Truncate(time.Millisecond),
+		Note:      req.GetNote(),
+		Running:   true,
+	}
+	if _, err := timeEntryCollection.InsertOne(ctx, data); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, status.Errorf(
+				codes.FailedPrecondition,
+				"[ERROR] a timer is already running",
+			)
+		}
+		return nil, dbError(ctx, err, "cannot create time entry in MongoDB")
+	}
+
+	return &api.StartTimerResponse{
+		Entry: getTimeEntryGRPC(&data),
+	}, nil
+}
+
+func (*timeTrackingServer) StopTimer(ctx context.Context, _ *api.StopTimerRequest) (*api.StopTimerResponse, error) {
+
+	log.Println("[INFO] stop timer")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *api.StopTimerResponse
+	err = inTransaction(ctx, func(ctx context.Context) error {
+		data := &timeEntry{}
+		filter := bson.M{"user_id": actor, "running": true}
+		if err := timeEntryCollection.FindOne(ctx, filter).Decode(data); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return status.Errorf(
+					codes.FailedPrecondition,
+					"[ERROR] no timer is running",
+				)
+			}
+			return dbError(ctx, err, "cannot read running timer")
+		}
+
+		end := time.Now().UTC().Truncate(time.Millisecond)
+		data.EndTime = &end
+		data.Duration = end.Sub(data.StartTime)
+		data.Running = false
+		update := bson.M{
+			"$set":   bson.M{"end_time": end, "duration": data.Duration},
+			"$unset": bson.M{"running": ""},
+		}
+		if _, err := timeEntryCollection.UpdateOne(ctx, bson.M{"_id": data.ID}, update); err != nil {
+			return dbError(ctx, err, "cannot update time entry in MongoDB")
+		}
+		if err := addTrackedTime(ctx, data.TaskID, data.Duration); err != nil {
+			return err
+		}
+		res = &api.StopTimerResponse{Entry: getTimeEntryGRPC(data)}
+		return nil
+	})
+	return res, err
+}
+
+func (*timeTrackingServer) AddTimeEntry(ctx context.Context, req *api.AddTimeEntryRequest) (*api.AddTimeEntryResponse, error) {
+
+	log.Println("[INFO] add time entry")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetStartTime() == nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] start time is required",
+		)
+	}
+	if req.GetDuration() == nil || req.GetDuration().AsDuration() <= 0 {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] duration must be positive",
+		)
+	}
+
+	var res *api.AddTimeEntryResponse
+	err = inTransaction(ctx, func(ctx context.Context) error {
+		t, err := trackedTask(ctx, req.GetTaskId())
+		if err != nil {
+			return err
+		}
+
+		start := req.GetStartTime().AsTime().UTC().Truncate(time.Millisecond)
+		d := req.GetDuration().AsDuration().Truncate(time.Millisecond)
+		end := start.Add(d)
+		data := timeEntry{
+			ID:        primitive.NewObjectID(),
+			TaskID:    t.ID,
+			UserID:    actor,
+			StartTime: start,
+			EndTime:   &end,
+			Duration:  d,
+			Note:      req.GetNote(),
+			Manual:    true,
+		}
+		if _, err := timeEntryCollection.InsertOne(ctx, data); err != nil {
+			return dbError(ctx, err, "cannot create time entry in MongoDB")
+		}
+		if err := addTrackedTime(ctx, t.ID, d); err != nil {
+			return err
+		}
+		res = &api.AddTimeEntryResponse{Entry: getTimeEntryGRPC(&data)}
+		return nil
+	})
+	return res, err
+}
+
+func (*timeTrackingServer) DeleteTimeEntry(ctx context.Context, req *api.DeleteTimeEntryRequest) (*api.DeleteTimeEntryResponse, error) {
+
+	log.Println("[INFO] delete time entry")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse ID",
+		)
+	}
+
+	err = inTransaction(ctx, func(ctx context.Context) error {
+		data := &timeEntry{}
+		if err := timeEntryCollection.FindOne(ctx, bson.M{"_id": oid}).Decode(data); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return status.Errorf(
+					codes.NotFound,
+					"[ERROR] cannot find time entry with ID: %v", err,
+				)
+			}
+			return dbError(ctx, err, "cannot read time entry from MongoDB")
+		}
+		if data.UserID != actorFromContext(ctx) {
+			return status.Errorf(
+				codes.PermissionDenied,
+				"[ERROR] only the user who tracked the time can delete it",
+			)
+		}
+
+		if _, err := timeEntryCollection.DeleteOne(ctx, bson.M{"_id": oid}); err != nil {
+			return dbError(ctx, err, "cannot delete time entry in MongoDB")
+		}
+		if data.Running {
+			return nil
+		}
+		return addTrackedTime(ctx, data.TaskID, -data.Duration)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.DeleteTimeEntryResponse{
+		Id: req.GetId(),
+	}, nil
+}
+
+func (*timeTrackingServer) ListTimeEntries(req *api.ListTimeEntriesRequest, stream api.TimeTrackingService_ListTimeEntriesServer) error {
+
+	log.Println("[INFO] stream list time entries")
+
+	ctx := stream.Context()
+
+	taskID, err := primitive.ObjectIDFromHex(req.GetTaskId())
+	if err != nil {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse task ID",
+		)
+	}
+	findCtx, cancel := withQueryTimeout(ctx)
+	t, err := findTask(findCtx, bson.M{"_id": taskID})
+	cancel()
+	if err != nil {
+		return err
+	}
+	if err := checkAccess(ctx, t, api.ShareRole_SHARE_ROLE_VIEWER); err != nil {
+		return err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "start_time", Value: 1}, {Key: "_id", Value: 1}})
+	return streamDocs(ctx, timeEntryCollection, bson.M{"task_id": taskID}, opts, func(data *timeEntry) error {
+		return stream.Send(&api.ListTimeEntriesResponse{
+			Entry: getTimeEntryGRPC(data),
+		})
+	})
+}
+
+// timeReportKeys maps report groups to the fields of the pipeline
+// documents they group by.
+var timeReportKeys = map[api.TimeReportGroup]string{
+	api.TimeReportGroup_TIME_REPORT_GROUP_TASK:    "task_id",
+	api.TimeReportGroup_TIME_REPORT_GROUP_PROJECT: "project_id",
+	api.TimeReportGroup_TIME_REPORT_GROUP_USER:    "user_id",
+	api.TimeReportGroup_TIME_REPORT_GROUP_DAY:     "date",
+}
+
+type timeReportRow struct {
+	ID struct {
+		TaskID    primitive.ObjectID `bson:"task_id"`
+		ProjectID primitive.ObjectID `bson:"project_id"`
+		UserID    string             `bson:"user_id"`
+		Date      string             `bson:"date"`
+	} `bson:"_id"`
+	Duration time.Duration `bson:"duration"`
+	Count    int64         `bson:"count"`
+}
+
+func (*timeTrackingServer) TimeReport(ctx context.Context, req *api.TimeReportRequest) (*api.TimeReportResponse, error) {
+
+	log.Println("[INFO] time report")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	start, end, err := dayRange(req.GetStartTime(), req.GetEndTime(), time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	// Rows sort by the groups in the requested order.
+	group := bson.D{}
+	seen := make(map[string]bool)
+	for _, g := range req.GetGroupBy() {
+		key, ok := timeReportKeys[g]
+		if !ok {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] unknown report group: %v", g,
+			)
+		}
+		if !seen[key] {
+			seen[key] = true
+			group = append(group, bson.E{Key: key, Value: "$" + key})
+		}
+	}
+
+	match := bson.M{
+		"running":    bson.M{"$exists": false},
+		"start_time": bson.M{"$gte": start, "$lt": end},
+	}
+	if req.GetUserId() != "" {
+		match["user_id"] = req.GetUserId()
+	}
+	// Only the entries on tasks the caller can read are counted, and the
+	// project is taken from the task.
+	taskMatch := bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$task_id"}}}
+	if req.GetProjectId() != "" {
+		oid, err := primitive.ObjectIDFromHex(req.GetProjectId())
+		if err != nil {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] cannot parse project ID",
+			)
+		}
+		taskMatch["project_id"] = oid
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": collection.Name(),
+			"let":  bson.M{"task_id": "$task_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": visibleFilter(ctx, taskMatch)},
+				bson.M{"$project": bson.M{"project_id": 1}},
+			},
+			"as": "task",
+		}}},
+		{{Key: "$unwind", Value: "$task"}},
+		{{Key: "$set", Value: bson.M{
+			"project_id": "$task.project_id",
+			"date":       bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$start_time"}},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      group,
+			"duration": bson.M{"$sum": "$duration"},
+			"count":    bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+	cur, err := timeEntryCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, dbError(ctx, err, "cannot aggregate time entries")
+	}
+	var rows []timeReportRow
+	if err := cur.All(ctx, &rows); err != nil {
+		return nil, dbError(ctx, err, "cannot aggregate time entries")
+	}
+
+	res := &api.TimeReportResponse{}
+	var total time.Duration
+	for _, r := range rows {
+		total += r.Duration
+		res.Rows = append(res.Rows, &api.TimeReportRow{
+			TaskId:     hexOrEmpty(r.ID.TaskID),
+			ProjectId:  hexOrEmpty(r.ID.ProjectID),
+			UserId:     r.ID.UserID,
+			Date:       r.ID.Date,
+			Duration:   durationpb.New(r.Duration),
+			EntryCount: r.Count,
+		})
+	}
+	res.Total = durationpb.New(total)
+	return res, nil
+}
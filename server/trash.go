@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// purgeBatchSize is how many tasks purgeTrash removes per transaction.
+const purgeBatchSize = 100
+
+var (
+	defaultTrashRetention     = 30 * 24 * time.Hour
+	defaultTrashPurgeInterval = time.Hour
+	trashRetention            = defaultTrashRetention
+	trashPurgeInterval        = defaultTrashPurgeInterval
+)
+
+// trashedFilter restricts a query to tasks that are in the trash.
+func trashedFilter(filter bson.M) bson.M {
+	filter["delete_time"] = bson.M{"$exists": true}
+	return filter
+}
+
+func (*server) RestoreTask(ctx context.Context, req *api.RestoreTaskRequest) (*api.RestoreTaskResponse, error) {
+
+	log.Println("[INFO] restore task")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var res *api.RestoreTaskResponse
+	err := inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = restoreTask(ctx, req)
+		return err
+	})
+	return res, err
+}
+
+// restoreTask moves a task out of the trash together with the subtasks that
+// were trashed with it, recording each like an update.
+func restoreTask(ctx context.Context, req *api.RestoreTaskRequest) (*api.RestoreTaskResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse ID",
+		)
+	}
+
+	data, err := findTask(ctx, trashedFilter(bson.M{"_id": oid}))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccess(ctx, data, api.ShareRole_SHARE_ROLE_EDITOR); err != nil {
+		return nil, err
+	}
+	if !data.ParentID.IsZero() {
+		n, err := collection.CountDocuments(ctx, trashedFilter(bson.M{"_id": data.ParentID}))
+		if err != nil {
+			return nil, dbError(ctx, err, "cannot read parent task")
+		}
+		if n > 0 {
+			return nil, status.Errorf(
+				codes.FailedPrecondition,
+				"[ERROR] parent task %s is in the trash, restore it first", data.ParentID.Hex(),
+			)
+		}
+	}
+
+	// A cascading delete trashes the whole subtree at the same time.
+	desc, err := descendants(ctx, oid, false, 0)
+	if err != nil {
+		return nil, err
+	}
+	list := []*task{data}
+	for i := range desc {
+		if sameTime(desc[i].DeleteTime, data.DeleteTime) {
+			list = append(list, &desc[i].task)
+		}
+	}
+
+	now := time.Now().UTC()
+	ids := make([]primitive.ObjectID, len(list))
+	for i, t := range list {
+		if err := restoreOne(ctx, t, now, req.GetIgnoreWipLimits()); err != nil {
+			return nil, err
+		}
+		ids[i] = t.ID
+	}
+	if err := refreshDependents(ctx, ids...); err != nil {
+		return nil, err
+	}
+
+	return &api.RestoreTaskResponse{
+		Task: getTaskGRPC(data),
+	}, nil
+}
+
+// restoreOne moves data out of the trash at time now.
+func restoreOne(ctx context.Context, data *task, now time.Time, ignoreWIPLimits bool) error {
+	if !ignoreWIPLimits {
+		// A restored task enters its column as if it came from nowhere.
+		if err := checkWIPLimits(ctx, &task{}, data); err != nil {
+			return err
+		}
+	}
+	filter := trashedFilter(bson.M{"_id": data.ID})
+	before := *data
+	data.DeleteTime = nil
+	data.UpdateTime = now
+	data.Revision++
+	data.EventSeq++
+
+	if _, err := collection.ReplaceOne(ctx, filter, data); err != nil {
+		return dbError(ctx, err, "cannot restore object in MongoDB")
+	}
+	if err := recordTaskEvent(ctx, eventTaskUpdated, data); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, data.ID, &before, data); err != nil {
+		return err
+	}
+	return recordRevision(ctx, data)
+}
+
+func (*server) ListDeletedTasks(_ *api.ListDeletedTasksRequest, stream api.TaskService_ListDeletedTasksServer) error {
+
+	log.Println("[INFO] stream list deleted tasks")
+
+	return streamTasks(stream.Context(), visibleFilter(stream.Context(), trashedFilter(bson.M{})), nil, func(data *task) error {
+		return stream.Send(&api.ListDeletedTasksResponse{
+			Task: getTaskGRPC(data),
+		})
+	})
+}
+
+// runTrashPurger permanently removes tasks that have been in the trash longer
+// than retention, along with the attachment content of deleted tasks,
+// checking every interval until ctx is cancelled.
+func runTrashPurger(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purgeTrash(ctx, retention)
+		sweepAttachments(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeTrash permanently removes the tasks that have been in the trash
+// longer than retention, in transactions of up to purgeBatchSize tasks.
+func purgeTrash(ctx context.Context, retention time.Duration) {
+	cutoff := time.Now().UTC().Add(-retention)
+	for {
+		n, err := purgeBatch(ctx, cutoff)
+		if err != nil {
+			log.Printf("[ERROR] cannot purge trash: %v\n", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("[INFO] purged %d tasks from trash\n", n)
+		}
+		if n < purgeBatchSize {
+			return
+		}
+	}
+}
+
+// purgeBatch permanently removes up to purgeBatchSize tasks trashed before
+// cutoff in one transaction, so a task restored meanwhile is kept.
+func purgeBatch(ctx context.Context, cutoff time.Time) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var purged int64
+	err := inTransaction(ctx, func(ctx context.Context) error {
+		purged = 0
+		opts := options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(purgeBatchSize)
+		cur, err := collection.Find(ctx, bson.M{"delete_time": bson.M{"$lt": cutoff}}, opts)
+		if err != nil {
+			return dbError(ctx, err, "cannot read trash")
+		}
+		var list []task
+		if err := cur.All(ctx, &list); err != nil {
+			return dbError(ctx, err, "cannot read trash")
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]interface{}, len(list))
+		for i := range list {
+			ids[i] = list[i].ID
+		}
+
+		// Subtasks of purged tasks become top-level tasks.
+		_, err = collection.UpdateMany(ctx, bson.M{"parent_id": bson.M{"$in": ids}}, bson.M{"$unset": bson.M{"parent_id": ""}})
+		if err != nil {
+			return dbError(ctx, err, "cannot detach subtasks")
+		}
+		purged, err = dropTasks(ctx, ids)
+		return err
+	})
+	return purged, err
+}
+
+// dropTasks permanently deletes tasks along with the records that belong to
+// them, and returns how many tasks it deleted. It must run in a transaction.
+func dropTasks(ctx context.Context, ids []interface{}) (int64, error) {
+	res, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, dbError(ctx, err, "cannot delete object in MongoDB")
+	}
+	for _, drop := range []func(context.Context, []interface{}) error{
+		dropDependencies,
+		dropRevisions,
+		dropComments,
+		dropAttachments,
+		dropTimeEntries,
+		dropSprintTasks,
+	} {
+		if err := drop(ctx, ids); err != nil {
+			return 0, err
+		}
+	}
+	return res.DeletedCount, nil
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// taskParent resolves the parent of task id (zero for a new task). The
+// parent must be an active task the caller can change and must not be id
+// itself or one of its descendants. Moving an existing task takes the parents
+// lock so that concurrent moves cannot close a cycle between them.
+func taskParent(ctx context.Context, id primitive.ObjectID, parentID string) (primitive.ObjectID, error) {
+	if parentID == "" {
+		return primitive.NilObjectID, nil
+	}
+	parent, err := primitive.ObjectIDFromHex(parentID)
+	if err != nil {
+		return primitive.NilObjectID, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse parent ID",
+		)
+	}
+	if parent == id {
+		return primitive.NilObjectID, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] task cannot be its own parent",
+		)
+	}
+	if !id.IsZero() {
+		if err := takeLock(ctx, "parents"); err != nil {
+			return primitive.NilObjectID, err
+		}
+	}
+
+	cur, err := collection.Aggregate(ctx, bson.A{
+		bson.M{"$match": activeFilter(bson.M{"_id": parent})},
+		bson.M{"$graphLookup": bson.M{
+			"from":             collection.Name(),
+			"startWith":        "$parent_id",
+			"connectFromField": "parent_id",
+			"connectToField":   "_id",
+			"as":               "ancestors",
+		}},
+		bson.M{"$project": bson.M{
+			"ancestors._id": 1,
+			"owner_id":      1,
+			"assignee_id":   1,
+			"acl":           1,
+		}},
+	})
+	if err != nil {
+		return primitive.NilObjectID, dbError(ctx, err, "cannot read parent task")
+	}
+	var res []struct {
+		task      `bson:",inline"`
+		Ancestors []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		} `bson:"ancestors"`
+	}
+	if err := cur.All(ctx, &res); err != nil {
+		return primitive.NilObjectID, dbError(ctx, err, "cannot read parent task")
+	}
+	if len(res) == 0 {
+		return primitive.NilObjectID, status.Errorf(
+			codes.FailedPrecondition,
+			"[ERROR] parent task %s does not exist", parentID,
+		)
+	}
+	if err := checkAccess(ctx, &res[0].task, api.ShareRole_SHARE_ROLE_EDITOR); err != nil {
+		return primitive.NilObjectID, err
+	}
+	for _, a := range res[0].Ancestors {
+		if a.ID == id {
+			return primitive.NilObjectID, status.Errorf(
+				codes.FailedPrecondition,
+				"[ERROR] parent task %s is a subtask of this task", parentID,
+			)
+		}
+	}
+	return parent, nil
+}
+
+// descendants returns every task below id, optionally only active ones,
+// along with its depth relative to id (0 for direct children).
+func descendants(ctx context.Context, id primitive.ObjectID, activeOnly bool, maxDepth int32) ([]treeEntry, error) {
+	lookup := bson.M{
+		"from":             collection.Name(),
+		"startWith":        "$_id",
+		"connectFromField": "_id",
+		"connectToField":   "parent_id",
+		"as":               "descendants",
+		"depthField":       "depth",
+	}
+	if activeOnly {
+		lookup["restrictSearchWithMatch"] = activeFilter(bson.M{})
+	}
+	if maxDepth > 0 {
+		lookup["maxDepth"] = maxDepth - 1
+	}
+
+	cur, err := collection.Aggregate(ctx, bson.A{
+		bson.M{"$match": bson.M{"_id": id}},
+		bson.M{"$graphLookup": lookup},
+	})
+	if err != nil {
+		return nil, dbError(ctx, err, "cannot read subtasks")
+	}
+	var res []struct {
+		Descendants []treeEntry `bson:"descendants"`
+	}
+	if err := cur.All(ctx, &res); err != nil {
+		return nil, dbError(ctx, err, "cannot read subtasks")
+	}
+	if len(res) == 0 {
+		return nil, nil
+	}
+	return res[0].Descendants, nil
+}
+
+type treeEntry struct {
+	task  `bson:",inline"`
+	Depth int `bson:"depth"`
+}
+
+// reparentChildren moves the direct children of id under newParent, or
+// makes them top-level tasks when newParent is zero.
+func reparentChildren(ctx context.Context, id, newParent primitive.ObjectID) error {
+	update := bson.M{"$unset": bson.M{"parent_id": ""}}
+	if !newParent.IsZero() {
+		update = bson.M{"$set": bson.M{"parent_id": newParent}}
+	}
+	_, err := collection.UpdateMany(ctx, bson.M{"parent_id": id}, update)
+	if err != nil {
+		return dbError(ctx, err, "cannot move subtasks")
+	}
+	return nil
+}
+
+// deleteScope applies the children policy of a delete request to data and
+// returns the IDs of all tasks that have to be deleted with it.
+func deleteScope(ctx context.Context, data *task, req *api.DeleteTaskRequest) ([]primitive.ObjectID, error) {
+	ids := []primitive.ObjectID{data.ID}
+
+	switch req.GetChildren() {
+	case api.ChildrenPolicy_CHILDREN_POLICY_UNSPECIFIED, api.ChildrenPolicy_CHILDREN_POLICY_REJECT:
+		n, err := collection.CountDocuments(ctx, activeFilter(bson.M{"parent_id": data.ID}))
+		if err != nil {
+			return nil, dbError(ctx, err, "cannot count subtasks")
+		}
+		if n > 0 {
+			return nil, status.Errorf(
+				codes.FailedPrecondition,
+				"[ERROR] task has %d subtasks", n,
+			)
+		}
+		if req.GetForce() {
+			// Only trashed subtasks are left; keep them attached to the tree.
+			if err := reparentChildren(ctx, data.ID, data.ParentID); err != nil {
+				return nil, err
+			}
+		}
+	case api.ChildrenPolicy_CHILDREN_POLICY_CASCADE:
+		desc, err := descendants(ctx, data.ID, !req.GetForce(), 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range desc {
+			ids = append(ids, d.ID)
+		}
+	case api.ChildrenPolicy_CHILDREN_POLICY_REPARENT:
+		if err := reparentChildren(ctx, data.ID, data.ParentID); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] unknown children policy: %v", req.GetChildren(),
+		)
+	}
+
+	return ids, nil
+}
+
+func (*server) GetTaskTree(ctx context.Context, req *api.GetTaskTreeRequest) (*api.GetTaskTreeResponse, error) {
+
+	log.Println("[INFO] get task tree")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse ID",
+		)
+	}
+	if req.GetMaxDepth() < 0 {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] max depth must not be negative",
+		)
+	}
+
+	data, err := findTask(ctx, activeFilter(bson.M{"_id": oid}))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccess(ctx, data, api.ShareRole_SHARE_ROLE_VIEWER); err != nil {
+		return nil, err
+	}
+	desc, err := descendants(ctx, oid, true, req.GetMaxDepth())
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(desc, func(i, j int) bool {
+		return desc[i].ID.Hex() < desc[j].ID.Hex()
+	})
+	root := &api.TaskNode{Task: getTaskGRPC(data)}
+	nodes := map[primitive.ObjectID]*api.TaskNode{oid: root}
+	actor := actorFromContext(ctx)
+	for i := range desc {
+		// Subtrees below a task the caller cannot read are left out.
+		if taskRole(&desc[i].task, actor) < api.ShareRole_SHARE_ROLE_VIEWER {
+			continue
+		}
+		nodes[desc[i].ID] = &api.TaskNode{Task: getTaskGRPC(&desc[i].task)}
+	}
+	for i := range desc {
+		node, ok := nodes[desc[i].ID]
+		if !ok {
+			continue
+		}
+		if parent, ok := nodes[desc[i].ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	return &api.GetTaskTreeResponse{
+		Root: root,
+	}, nil
+}
+
+// cloneFields returns the fields of src that a copy of it keeps.
+func cloneFields(src *task, projectID, parentID string) *api.Task {
+	reminders := getRemindersGRPC(src.Reminders)
+	for _, r := range reminders {
+		r.Id = ""
+	}
+	return &api.Task{
+		Name:        src.Name,
+		Desc:        src.Desc,
+		Priority:    src.Priority,
+		DueTime:     timeToProto(src.DueTime),
+		Status:      api.Status_STATUS_TODO,
+		Labels:      src.Labels,
+		ProjectId:   projectID,
+		ParentId:    parentID,
+		AssigneeId:  src.AssigneeID,
+		StoryPoints: src.StoryPoints,
+		Reminders:   reminders,
+	}
+}
+
+func (*server) CloneTask(ctx context.Context, req *api.CloneTaskRequest) (*api.CloneTaskResponse, error) {
+
+	log.Println("[INFO] clone task")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var res *api.CloneTaskResponse
+	err := inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = cloneTask(ctx, req)
+		return err
+	})
+	return res, err
+}
+
+// cloneTask copies a task and the subtasks the caller can read, creating
+// each parent before its subtasks.
+func cloneTask(ctx context.Context, req *api.CloneTaskRequest) (*api.CloneTaskResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse ID",
+		)
+	}
+	data, err := findTask(ctx, activeFilter(bson.M{"_id": oid}))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccess(ctx, data, api.ShareRole_SHARE_ROLE_VIEWER); err != nil {
+		return nil, err
+	}
+	projectID := req.GetProjectId()
+	if projectID == "" {
+		projectID = data.ProjectID.Hex()
+	}
+
+	root, err := createTask(ctx, &api.CreateTaskRequest{
+		Task:            cloneFields(data, projectID, hexOrEmpty(data.ParentID)),
+		IgnoreWipLimits: req.GetIgnoreWipLimits(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := &api.CloneTaskResponse{Task: root.GetTask()}
+
+	desc, err := descendants(ctx, oid, true, 0)
+	if err != nil {
+		return nil, err
+	}
+	// Parents sort before their subtasks, and siblings keep their order.
+	sort.Slice(desc, func(i, j int) bool {
+		if desc[i].Depth != desc[j].Depth {
+			return desc[i].Depth < desc[j].Depth
+		}
+		if desc[i].Rank != desc[j].Rank {
+			return desc[i].Rank < desc[j].Rank
+		}
+		return desc[i].ID.Hex() < desc[j].ID.Hex()
+	})
+	copies := map[primitive.ObjectID]string{oid: root.GetTask().GetId()}
+	for i := range desc {
+		sub := &desc[i].task
+		parentID, ok := copies[sub.ParentID]
+		if !ok || taskRole(sub, actorFromContext(ctx)) < api.ShareRole_SHARE_ROLE_VIEWER {
+			// Subtasks the caller cannot read are left out, with their own
+			// subtasks.
+			continue
+		}
+		c, err := createTask(ctx, &api.CreateTaskRequest{
+			Task:            cloneFields(sub, projectID, parentID),
+			IgnoreWipLimits: req.GetIgnoreWipLimits(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		copies[sub.ID] = c.GetTask().GetId()
+		res.Subtasks = append(res.Subtasks, c.GetTask())
+	}
+	return res, nil
+}
@@ -0,0 +1,648 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	eventTaskCreated = "task.created"
+	eventTaskUpdated = "task.updated"
+	eventTaskDeleted = "task.deleted"
+)
+
+var webhookEvents = map[string]bool{
+	eventTaskCreated: true,
+	eventTaskUpdated: true,
+	eventTaskDeleted: true,
+}
+
+var (
+	defaultWebhookInterval = 5 * time.Second
+	webhookInterval        = defaultWebhookInterval
+	webhookCollection      *mongo.Collection
+	deliveryCollection     *mongo.Collection
+)
+
+type webhook struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	URL        string             `bson:"url"`
+	Events     []string           `bson:"events,omitempty"`
+	Secret     string             `bson:"secret"`
+	Active     bool               `bson:"active"`
+	CreateTime time.Time          `bson:"create_time"`
+	OwnerID    string             `bson:"owner_id,omitempty"`
+}
+
+func getWebhookGRPC(data *webhook) *api.Webhook {
+	return &api.Webhook{
+		Id:         data.ID.Hex(),
+		Url:        data.URL,
+		Events:     data.Events,
+		Active:     data.Active,
+		CreateTime: timeToProto(&data.CreateTime),
+		OwnerId:    data.OwnerID,
+	}
+}
+
+type delivery struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty"`
+	WebhookID       primitive.ObjectID `bson:"webhook_id"`
+	Event           string             `bson:"event"`
+	Payload         string             `bson:"payload"`
+	State           api.DeliveryState  `bson:"state"`
+	Attempts        int32              `bson:"attempts"`
+	ResponseCode    int32              `bson:"response_code,omitempty"`
+	LastError       string             `bson:"last_error,omitempty"`
+	CreateTime      time.Time          `bson:"create_time"`
+	NextAttemptTime *time.Time         `bson:"next_attempt_time,omitempty"`
+	DeliveredTime   *time.Time         `bson:"delivered_time,omitempty"`
+	LeaseUntil      *time.Time         `bson:"lease_until,omitempty"`
+}
+
+func getDeliveryGRPC(data *delivery) *api.WebhookDelivery {
+	return &api.WebhookDelivery{
+		Id:              data.ID.Hex(),
+		WebhookId:       data.WebhookID.Hex(),
+		Event:           data.Event,
+		Payload:         data.Payload,
+		State:           data.State,
+		Attempts:        data.Attempts,
+		ResponseCode:    data.ResponseCode,
+		LastError:       data.LastError,
+		CreateTime:      timeToProto(&data.CreateTime),
+		NextAttemptTime: timeToProto(data.NextAttemptTime),
+		DeliveredTime:   timeToProto(data.DeliveredTime),
+	}
+}
+
+// webhookPayload is the JSON body POSTed to webhook endpoints.
+type webhookPayload struct {
+	ID    string          `json:"id"`
+	Event string          `json:"event"`
+	Time  time.Time       `json:"time"`
+	Task  json.RawMessage `json:"task"`
+}
+
+// enqueueWebhooks queues a delivery of event for every active webhook
+// subscribed to it whose owner can read the task.
+func enqueueWebhooks(ctx context.Context, event string, taskJSON json.RawMessage) error {
+	data, err := eventTask(taskJSON)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{
+		"active": true,
+		"$or":    bson.A{bson.M{"events": bson.M{"$exists": false}}, bson.M{"events": event}},
+	}
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "owner_id": 1})
+	cur, err := webhookCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	var hooks []webhook
+	if err := cur.All(ctx, &hooks); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var docs []interface{}
+	for _, h := range hooks {
+		if taskRole(data, h.OwnerID) < api.ShareRole_SHARE_ROLE_VIEWER {
+			continue
+		}
+		id := primitive.NewObjectID()
+		body, err := json.Marshal(webhookPayload{
+			ID:    id.Hex(),
+			Event: event,
+			Time:  now,
+			Task:  taskJSON,
+		})
+		if err != nil {
+			return err
+		}
+		docs = append(docs, delivery{
+			ID:              id,
+			WebhookID:       h.ID,
+			Event:           event,
+			Payload:         string(body),
+			State:           api.DeliveryState_DELIVERY_STATE_PENDING,
+			CreateTime:      now,
+			NextAttemptTime: &now,
+		})
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	_, err = deliveryCollection.InsertMany(ctx, docs)
+	return err
+}
+
+// eventTask returns the owner, assignee and ACL of the task in an event,
+// which decide who may receive it.
+func eventTask(taskJSON json.RawMessage) (*task, error) {
+	t := &api.Task{}
+	if err := protojson.Unmarshal(taskJSON, t); err != nil {
+		return nil, err
+	}
+	data := &task{OwnerID: t.GetOwnerId(), AssigneeID: t.GetAssigneeId()}
+	for _, e := range t.GetAcl() {
+		data.ACL = append(data.ACL, shareEntry{UserID: e.GetUserId(), Role: e.GetRole()})
+	}
+	return data, nil
+}
+
+// sign returns the value of the X-Webhook-Signature header for body.
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// publicAddress reports whether webhooks may be sent to ip. Loopback,
+// link-local and private addresses would let callers reach the internal
+// network of the server.
+func publicAddress(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast()
+}
+
+// newWebhookClient returns an HTTP client that only connects to public
+// addresses, so a host name resolving to an internal address is refused as
+// well, including after a redirect.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicAddress(ip) {
+				return fmt.Errorf("webhook address %s is not public", host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Endpoints are dialed directly so the dialer sees their addresses.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Transport: transport}
+}
+
+func validateWebhook(w *api.Webhook) error {
+	u, err := url.Parse(w.GetUrl())
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] webhook URL must be an absolute http(s) URL",
+		)
+	}
+	host := strings.ToLower(u.Hostname())
+	ip := net.ParseIP(host)
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || (ip != nil && !publicAddress(ip)) {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] webhook URL must not point to a loopback, link-local or private address",
+		)
+	}
+	for _, e := range w.GetEvents() {
+		if !webhookEvents[e] {
+			return status.Errorf(
+				codes.InvalidArgument,
+				"[ERROR] unknown event: %s", e,
+			)
+		}
+	}
+	return nil
+}
+
+// webhookDispatcher sends queued deliveries, retrying failed ones with
+// exponential backoff. Deliveries are claimed with a lease so several
+// replicas can run dispatchers side by side.
+type webhookDispatcher struct {
+	client *http.Client
+	// timeout bounds each POST to an endpoint.
+	timeout     time.Duration
+	interval    time.Duration
+	lease       time.Duration
+	maxAttempts int32
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+}
+
+func newWebhookDispatcher(client *http.Client, interval time.Duration) *webhookDispatcher {
+	return &webhookDispatcher{
+		client:      client,
+		timeout:     10 * time.Second,
+		interval:    interval,
+		lease:       time.Minute,
+		maxAttempts: 8,
+		minBackoff:  10 * time.Second,
+		maxBackoff:  time.Hour,
+	}
+}
+
+func (d *webhookDispatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			sent, err := d.sendNext(ctx)
+			if err != nil {
+				log.Printf("[ERROR] cannot send webhook: %v\n", err)
+			}
+			if !sent || err != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// backoff returns how long to wait before the next attempt.
+func (d *webhookDispatcher) backoff(attempts int32) time.Duration {
+	wait := d.minBackoff
+	for i := int32(1); i < attempts && wait < d.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > d.maxBackoff {
+		wait = d.maxBackoff
+	}
+	return wait
+}
+
+// sendNext claims one due delivery and attempts it. It reports false when
+// nothing was due.
+func (d *webhookDispatcher) sendNext(ctx context.Context) (bool, error) {
+	data, hook, err := d.claim(ctx)
+	if data == nil || err != nil {
+		return false, err
+	}
+
+	var code int
+	if hook != nil && hook.Active {
+		code, err = d.post(ctx, hook, data)
+	} else {
+		err = errors.New("webhook was deleted or deactivated")
+		data.Attempts = d.maxAttempts - 1
+	}
+
+	updateCtx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+	_, err = deliveryCollection.UpdateOne(updateCtx, bson.M{"_id": data.ID}, bson.M{
+		"$set":   d.outcome(data, code, err, time.Now().UTC()),
+		"$unset": bson.M{"lease_until": ""},
+	})
+	return true, err
+}
+
+// claim leases the next due delivery along with its webhook, which is nil
+// if it was deleted. It returns a nil delivery when nothing is due.
+func (d *webhookDispatcher) claim(ctx context.Context) (*delivery, *webhook, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	now := time.Now().UTC()
+	filter := bson.M{
+		"state":             api.DeliveryState_DELIVERY_STATE_PENDING,
+		"next_attempt_time": bson.M{"$lte": now},
+		"$or": bson.A{
+			bson.M{"lease_until": bson.M{"$exists": false}},
+			bson.M{"lease_until": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"lease_until": now.Add(d.lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_time", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	data := &delivery{}
+	if err := deliveryCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(data); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	hook := &webhook{}
+	if err := webhookCollection.FindOne(ctx, bson.M{"_id": data.WebhookID}).Decode(hook); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return data, nil, nil
+		}
+		return nil, nil, err
+	}
+	return data, hook, nil
+}
+
+// outcome counts an attempt of data that ended with code and err at time
+// now, and returns the delivery fields to store: succeeded, failed for
+// good, or due again after a backoff.
+func (d *webhookDispatcher) outcome(data *delivery, code int, err error, now time.Time) bson.M {
+	data.Attempts++
+	set := bson.M{"attempts": data.Attempts, "response_code": code}
+	switch {
+	case err == nil:
+		set["state"] = api.DeliveryState_DELIVERY_STATE_SUCCEEDED
+		set["delivered_time"] = now
+		set["last_error"] = ""
+	case data.Attempts >= d.maxAttempts:
+		set["state"] = api.DeliveryState_DELIVERY_STATE_FAILED
+		set["last_error"] = err.Error()
+	default:
+		set["next_attempt_time"] = now.Add(d.backoff(data.Attempts))
+		set["last_error"] = err.Error()
+	}
+	return set
+}
+
+// post sends one attempt of a delivery and returns the response status.
+// Any non-2xx response counts as a failure.
+func (d *webhookDispatcher) post(ctx context.Context, hook *webhook, data *delivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	body := []byte(data.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", data.Event)
+	req.Header.Set("X-Webhook-Delivery", data.ID.Hex())
+	req.Header.Set("X-Webhook-Signature", sign(hook.Secret, body))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("endpoint responded with %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
+
+type webhookServer struct {
+	api.WebhookServiceServer
+}
+
+// findWebhook loads webhook id and checks that the caller owns it.
+func findWebhook(ctx context.Context, id string) (*webhook, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse ID",
+		)
+	}
+	data := &webhook{}
+	if err := webhookCollection.FindOne(ctx, bson.M{"_id": oid}).Decode(data); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Errorf(
+				codes.NotFound,
+				"[ERROR] cannot find webhook with ID: %v", err,
+			)
+		}
+		return nil, dbError(ctx, err, "cannot read webhook from MongoDB")
+	}
+	if err := checkOwner(ctx, data.OwnerID, "webhook", data.ID); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (*webhookServer) CreateWebhook(ctx context.Context, req *api.CreateWebhookRequest) (*api.CreateWebhookResponse, error) {
+
+	log.Println("[INFO] create webhook")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	w := req.GetWebhook()
+	if err := validateWebhook(w); err != nil {
+		return nil, err
+	}
+
+	secret := w.GetSecret()
+	if secret == "" {
+		var err error
+		if secret, err = newSecret(); err != nil {
+			return nil, status.Errorf(codes.Internal, "[ERROR] cannot generate secret: %v", err)
+		}
+	}
+
+	data := webhook{
+		ID:         primitive.NewObjectID(),
+		URL:        w.GetUrl(),
+		Events:     w.GetEvents(),
+		Secret:     secret,
+		Active:     w.GetActive(),
+		CreateTime: time.Now().UTC(),
+		OwnerID:    actorFromContext(ctx),
+	}
+	if _, err := webhookCollection.InsertOne(ctx, data); err != nil {
+		return nil, dbError(ctx, err, "cannot create webhook in MongoDB")
+	}
+
+	res := getWebhookGRPC(&data)
+	res.Secret = data.Secret
+	return &api.CreateWebhookResponse{
+		Webhook: res,
+	}, nil
+}
+
+func (*webhookServer) GetWebhook(ctx context.Context, req *api.GetWebhookRequest) (*api.GetWebhookResponse, error) {
+
+	log.Println("[INFO] get webhook")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data, err := findWebhook(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.GetWebhookResponse{
+		Webhook: getWebhookGRPC(data),
+	}, nil
+}
+
+func (*webhookServer) UpdateWebhook(ctx context.Context, req *api.UpdateWebhookRequest) (*api.UpdateWebhookResponse, error) {
+
+	log.Println("[INFO] update webhook")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	w := req.GetWebhook()
+	data, err := findWebhook(ctx, w.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWebhook(w); err != nil {
+		return nil, err
+	}
+
+	data.URL = w.GetUrl()
+	data.Events = w.GetEvents()
+	data.Active = w.GetActive()
+	if w.GetSecret() != "" {
+		data.Secret = w.GetSecret()
+	}
+
+	if _, err := webhookCollection.ReplaceOne(ctx, bson.M{"_id": data.ID}, data); err != nil {
+		return nil, dbError(ctx, err, "cannot update webhook in MongoDB")
+	}
+
+	return &api.UpdateWebhookResponse{
+		Webhook: getWebhookGRPC(data),
+	}, nil
+}
+
+func (*webhookServer) DeleteWebhook(ctx context.Context, req *api.DeleteWebhookRequest) (*api.DeleteWebhookResponse, error) {
+
+	log.Println("[INFO] delete webhook")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	data, err := findWebhook(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := webhookCollection.DeleteOne(ctx, bson.M{"_id": data.ID}); err != nil {
+		return nil, dbError(ctx, err, "cannot delete webhook in MongoDB")
+	}
+	if _, err := deliveryCollection.DeleteMany(ctx, bson.M{"webhook_id": data.ID}); err != nil {
+		return nil, dbError(ctx, err, "cannot delete webhook deliveries in MongoDB")
+	}
+
+	return &api.DeleteWebhookResponse{
+		Id: req.GetId(),
+	}, nil
+}
+
+func (*webhookServer) ListWebhooks(_ *api.ListWebhooksRequest, stream api.WebhookService_ListWebhooksServer) error {
+
+	log.Println("[INFO] stream list webhooks")
+
+	filter := ownedFilter(stream.Context(), bson.M{})
+	return streamDocs(stream.Context(), webhookCollection, filter, nil, func(data *webhook) error {
+		return stream.Send(&api.ListWebhooksResponse{
+			Webhook: getWebhookGRPC(data),
+		})
+	})
+}
+
+func (*webhookServer) ListDeliveries(req *api.ListDeliveriesRequest, stream api.WebhookService_ListDeliveriesServer) error {
+
+	log.Println("[INFO] stream list webhook deliveries")
+
+	ctx, cancel := withQueryTimeout(stream.Context())
+	hook, err := findWebhook(ctx, req.GetWebhookId())
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "create_time", Value: -1}})
+	return streamDocs(stream.Context(), deliveryCollection, bson.M{"webhook_id": hook.ID}, opts, func(data *delivery) error {
+		return stream.Send(&api.ListDeliveriesResponse{
+			Delivery: getDeliveryGRPC(data),
+		})
+	})
+}
+
+func (*webhookServer) Redeliver(ctx context.Context, req *api.RedeliverRequest) (*api.RedeliverResponse, error) {
+
+	log.Println("[INFO] redeliver webhook")
+
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(req.GetDeliveryId())
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"[ERROR] cannot parse delivery ID",
+		)
+	}
+
+	prev := &delivery{}
+	if err := deliveryCollection.FindOne(ctx, bson.M{"_id": oid}).Decode(prev); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Errorf(
+				codes.NotFound,
+				"[ERROR] cannot find delivery with ID: %v", err,
+			)
+		}
+		return nil, dbError(ctx, err, "cannot read delivery from MongoDB")
+	}
+	if _, err := findWebhook(ctx, prev.WebhookID.Hex()); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	data := delivery{
+		ID:              primitive.NewObjectID(),
+		WebhookID:       prev.WebhookID,
+		Event:           prev.Event,
+		Payload:         prev.Payload,
+		State:           api.DeliveryState_DELIVERY_STATE_PENDING,
+		CreateTime:      now,
+		NextAttemptTime: &now,
+	}
+	if _, err := deliveryCollection.InsertOne(ctx, data); err != nil {
+		return nil, dbError(ctx, err, "cannot queue delivery in MongoDB")
+	}
+
+	return &api.RedeliverResponse{
+		Delivery: getDeliveryGRPC(&data),
+	}, nil
+}
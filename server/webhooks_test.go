@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dbashirov/grpc-tasks/api"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func testDelivery() *delivery {
+	return &delivery{
+		ID:        primitive.NewObjectID(),
+		WebhookID: primitive.NewObjectID(),
+		Event:     eventTaskUpdated,
+		Payload:   `{"id":"1","event":"task.updated","task":{"name":"deploy"}}`,
+		State:     api.DeliveryState_DELIVERY_STATE_PENDING,
+	}
+}
+
+func TestWebhookPostSigns(t *testing.T) {
+	hook := &webhook{Secret: "s3cret", Active: true}
+	data := testDelivery()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("cannot read body: %v", err)
+		}
+		if string(body) != data.Payload {
+			t.Errorf("body = %s, want %s", body, data.Payload)
+		}
+		mac := hmac.New(sha256.New, []byte(hook.Secret))
+		mac.Write(body)
+		want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		if got := r.Header.Get("X-Webhook-Signature"); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+		if got := r.Header.Get("X-Webhook-Event"); got != data.Event {
+			t.Errorf("event header = %q, want %q", got, data.Event)
+		}
+		if got := r.Header.Get("X-Webhook-Delivery"); got != data.ID.Hex() {
+			t.Errorf("delivery header = %q, want %q", got, data.ID.Hex())
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("content type = %q, want application/json", got)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	hook.URL = srv.URL
+
+	d := newWebhookDispatcher(srv.Client(), time.Second)
+	code, err := d.post(context.Background(), hook, data)
+	if err != nil {
+		t.Fatalf("post failed: %v", err)
+	}
+	if code != http.StatusNoContent {
+		t.Fatalf("code = %d, want %d", code, http.StatusNoContent)
+	}
+}
+
+func TestWebhookRetry(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	hook := &webhook{URL: srv.URL, Secret: "s3cret", Active: true}
+	data := testDelivery()
+	d := newWebhookDispatcher(srv.Client(), time.Second)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for i, wait := range []time.Duration{d.minBackoff, 2 * d.minBackoff} {
+		code, err := d.post(context.Background(), hook, data)
+		if err == nil {
+			t.Fatalf("attempt %d succeeded, want a failure", i+1)
+		}
+		set := d.outcome(data, code, err, now)
+		if set["response_code"] != http.StatusInternalServerError {
+			t.Errorf("attempt %d: response_code = %v, want 500", i+1, set["response_code"])
+		}
+		if _, ok := set["state"]; ok {
+			t.Errorf("attempt %d: state = %v, want it unchanged", i+1, set["state"])
+		}
+		if got := set["next_attempt_time"]; got != now.Add(wait) {
+			t.Errorf("attempt %d: next_attempt_time = %v, want %v", i+1, got, now.Add(wait))
+		}
+	}
+
+	code, err := d.post(context.Background(), hook, data)
+	if err != nil {
+		t.Fatalf("third attempt failed: %v", err)
+	}
+	set := d.outcome(data, code, err, now)
+	if set["state"] != api.DeliveryState_DELIVERY_STATE_SUCCEEDED {
+		t.Errorf("state = %v, want succeeded", set["state"])
+	}
+	if data.Attempts != 3 {
+		t.Errorf("attempts = %d, want 3", data.Attempts)
+	}
+	if calls != 3 {
+		t.Errorf("endpoint called %d times, want 3", calls)
+	}
+}
+
+func TestWebhookGivesUp(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	hook := &webhook{URL: srv.URL, Active: true}
+	data := testDelivery()
+	d := newWebhookDispatcher(srv.Client(), time.Second)
+	d.maxAttempts = 3
+
+	var set map[string]interface{}
+	for i := int32(0); i < d.maxAttempts; i++ {
+		code, err := d.post(context.Background(), hook, data)
+		set = d.outcome(data, code, err, time.Now())
+	}
+	if set["state"] != api.DeliveryState_DELIVERY_STATE_FAILED {
+		t.Fatalf("state = %v, want failed", set["state"])
+	}
+	if set["last_error"] == "" {
+		t.Fatalf("last_error is empty")
+	}
+}
+
+func TestWebhookTimeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	hook := &webhook{URL: srv.URL, Active: true}
+	d := newWebhookDispatcher(srv.Client(), time.Second)
+	d.timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	if _, err := d.post(context.Background(), hook, testDelivery()); err == nil {
+		t.Fatalf("post succeeded, want a timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("post took %v, want it cut off after %v", elapsed, d.timeout)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	d := newWebhookDispatcher(http.DefaultClient, time.Second)
+	tests := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{1, d.minBackoff},
+		{2, 2 * d.minBackoff},
+		{3, 4 * d.minBackoff},
+		{20, d.maxBackoff},
+	}
+	for _, tt := range tests {
+		if got := d.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url string
+		ok  bool
+	}{
+		{"https://hooks.example.com/tasks", true},
+		{"http://93.184.216.34:8080/", true},
+		{"ftp://hooks.example.com/", false},
+		{"/tasks", false},
+		{"http://localhost:8080/", false},
+		{"http://api.localhost/", false},
+		{"http://127.0.0.1/", false},
+		{"http://[::1]/", false},
+		{"http://0.0.0.0/", false},
+		{"http://10.0.0.5/", false},
+		{"http://172.16.1.1/", false},
+		{"http://192.168.1.10/", false},
+		{"http://169.254.169.254/latest/meta-data/", false},
+		{"http://[fe80::1]/", false},
+		{"http://[fd00::1]/", false},
+	}
+	for _, tt := range tests {
+		err := validateWebhook(&api.Webhook{Url: tt.url})
+		if (err == nil) != tt.ok {
+			t.Errorf("validateWebhook(%q) error = %v, want ok = %v", tt.url, err, tt.ok)
+		}
+	}
+}
+
+func TestWebhookClientRefusesPrivateAddresses(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer srv.Close()
+
+	hook := &webhook{URL: srv.URL, Active: true}
+	d := newWebhookDispatcher(newWebhookClient(), time.Second)
+	if _, err := d.post(context.Background(), hook, testDelivery()); err == nil {
+		t.Fatalf("post to %s succeeded, want it refused", srv.URL)
+	}
+	if calls != 0 {
+		t.Fatalf("endpoint called %d times, want 0", calls)
+	}
+}
+
+func TestWebhookReceivers(t *testing.T) {
+	data := &task{
+		ID:         primitive.NewObjectID(),
+		Name:       "secret plan",
+		OwnerID:    "alice",
+		AssigneeID: "bob",
+		ACL:        []shareEntry{{UserID: "carol", Role: api.ShareRole_SHARE_ROLE_VIEWER}},
+	}
+	b, err := protojson.Marshal(getTaskGRPC(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := eventTask(b)
+	if err != nil {
+		t.Fatalf("eventTask failed: %v", err)
+	}
+	for owner, want := range map[string]bool{"alice": true, "bob": true, "carol": true, "mallory": false, "": false} {
+		if ok := taskRole(got, owner) >= api.ShareRole_SHARE_ROLE_VIEWER; ok != want {
+			t.Errorf("webhook of %q receives the task = %v, want %v", owner, ok, want)
+		}
+	}
+
+	got, err = eventTask([]byte(`{"id":"1","name":"open"}`))
+	if err != nil {
+		t.Fatalf("eventTask failed: %v", err)
+	}
+	if taskRole(got, "") < api.ShareRole_SHARE_ROLE_VIEWER {
+		t.Errorf("a task without an owner is not sent to webhooks without an owner")
+	}
+}